@@ -6,13 +6,17 @@
 	"embed"
 	"errors"
 	"fmt"
+	"go/token"
 	"go/types"
 	"io"
 	"os"
 	"path/filepath"
 	"slices"
+	"strings"
 	"sync"
 	"text/template"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/marcozac/go-aliaser/importer"
 	"github.com/marcozac/go-aliaser/util/maps"
@@ -42,7 +46,28 @@ functions []*Func
 	types []*TypeName
 
 	names *maps.Safe[string, objectId]
-	mu    sync.RWMutex
+
+	// docs maps the position of the objects declared in the loaded packages
+	// to their doc comments.
+	docs map[token.Pos]*objectDoc
+
+	// locals maps the types of the loaded packages to the renamed aliases
+	// used in their place in the generated wrappers (see [localType]).
+	locals substitution
+
+	// platformFiles are the files of the objects declared only on some of
+	// the target platforms (see [WithPlatforms]).
+	platformFiles []*platformFile
+
+	// duplicates are the objects skipped with [OnDuplicateError], not yet
+	// returned as a [DuplicateError].
+	duplicates []Duplicate
+
+	// targetNames are the names declared in the target package (see
+	// [WithTargetDir]).
+	targetNames []string
+
+	mu sync.RWMutex
 }
 
 // New returns a new [Aliaser] with the given configuration.
@@ -53,8 +78,14 @@ functions []*Func
 // New may also return an error in these cases:
 //   - Package loading fails
 //   - The package has errors
-//   - More or less than one package is loaded with the given pattern
+//   - No package is loaded with the given patterns
+//   - More than one package is loaded, but merging is not enabled (see
+//     [MergePackages])
 //   - The loaded package has an unexpected object type
+//   - The loaded package has deprecated objects and the [DeprecatedError]
+//     policy is used
+//   - Any of the target platforms is invalid (see [WithPlatforms])
+//   - Any of the name filters is invalid (see [IncludeMatching])
 //
 // Example:
 //
@@ -83,70 +114,154 @@ func New(c *Config, opts ...Option) (*Aliaser, error) {
 		return nil, ErrNilConfig
 	case c.TargetPackage == "":
 		return nil, ErrEmptyTarget
-	case c.Pattern == "":
+	case c.Pattern == "" && len(c.Patterns) == 0:
 		return nil, ErrEmptyPattern
 	}
+	c = c.setDefaults().applyOptions(opts...)
 	a := &Aliaser{
-		Config:   c.setDefaults().applyOptions(opts...),
-		Importer: importer.New(),
+		Config:   c,
+		Importer: c.newImporter(),
 		names:    maps.NewSafe(make(map[string]objectId)),
+		docs:     make(map[token.Pos]*objectDoc),
+		locals:   make(substitution),
+	}
+	if c.filterErr != nil {
+		return nil, c.filterErr
 	}
-	if err := a.load(); err != nil {
+	if c.GoVersion == "" {
+		dir := c.dir
+		if dir == "" {
+			dir = "."
+		}
+		v, err := moduleGoVersion(dir)
+		if err != nil {
+			return nil, fmt.Errorf("go version: %w", err)
+		}
+		c.GoVersion = v
+	}
+	if err := a.loadTargetNames(); err != nil {
+		return nil, err
+	}
+	load := a.load
+	if len(a.platforms) > 0 {
+		load = a.loadPlatforms
+	}
+	if err := load(); err != nil {
 		return nil, err
 	}
+	a.freezeImports()
 	return a, nil
 }
 
-const loadMode = packages.NeedName | packages.NeedTypes
+// freezeImports reserves the names declared in the target package, by the
+// aliases or by the target directory, and freezes the aliases of the imported
+// packages before generating any code depending on them, so that the packages
+// imported while generating do not change them.
+func (a *Aliaser) freezeImports() {
+	imps := []*importer.Importer{a.Importer}
+	names := a.names.Keys()
+	for _, pf := range a.platformFiles {
+		imps = append(imps, pf.view.Importer)
+		names = append(names, pf.view.names.Keys()...)
+	}
+	for _, imp := range imps {
+		imp.Reserve(names...)
+		imp.Freeze()
+	}
+}
+
+const loadMode = packages.NeedName | packages.NeedTypes | packages.NeedSyntax
 
 func (a *Aliaser) load() error {
-	pkgs, err := packages.Load(&packages.Config{Mode: loadMode, Context: a.ctx}, a.Pattern)
+	patterns := a.patterns()
+	pkgs, err := packages.Load(a.packagesConfig(loadMode), patterns...)
 	if err != nil {
 		return fmt.Errorf("load packages: %w", err)
 	}
-	if len(pkgs) != 1 {
+	switch {
+	case len(pkgs) == 0:
+		return fmt.Errorf("%w: %s", ErrNoPackages, strings.Join(patterns, " "))
+	case len(pkgs) > 1 && !a.mergePackages && len(patterns) == 1:
 		return fmt.Errorf("expected one package, got %d", len(pkgs))
 	}
-	pkg := pkgs[0]
-	if errs := pkg.Errors; len(errs) > 0 {
-		return fmt.Errorf("package errors: %w", PackagesErrors(errs))
+	// sort the packages to resolve the conflicts in a deterministic order
+	slices.SortFunc(pkgs, func(p1, p2 *packages.Package) int {
+		return strings.Compare(p1.PkgPath, p2.PkgPath)
+	})
+	for _, pkg := range pkgs {
+		if errs := pkg.Errors; len(errs) > 0 {
+			return fmt.Errorf("package errors: %s: %w", pkg.PkgPath, PackagesErrors(errs))
+		}
+		if err := a.addPkgObjects(pkg); err != nil {
+			return err
+		}
 	}
-	return a.addPkgObjects(pkg)
+	return a.duplicateError(0)
 }
 
 func (a *Aliaser) addPkgObjects(pkg *packages.Package) error {
 	a.AddImport(pkg.Types)
+	a.collectDocs(pkg)
 	scope := pkg.Types.Scope()
+	var deprecated []string
 	for _, name := range pkg.Types.Scope().Names() {
 		o := scope.Lookup(name)
-		if !o.Exported() {
+		if !o.Exported() || a.excluded(o) {
 			continue
 		}
-		if _, ok := a.excludedNames[o.Name()]; ok {
+		if a.deprecationNotice(o) != "" {
+			switch a.onDeprecated {
+			case DeprecatedExclude:
+				continue
+			case DeprecatedError:
+				deprecated = append(deprecated, o.Name())
+				continue
+			}
+		}
+		t := a.transform(o)
+		if t.Drop {
 			continue
 		}
-		switch o := o.(type) {
-		case *types.Const:
-			if !a.excludeConstants {
-				a.AddConstants(o)
-			}
-		case *types.Var:
-			if !a.excludeVariables {
-				a.AddVariables(o)
-			}
-		case *types.Func:
-			if !a.excludeFunctions {
-				a.AddFunctions(o)
-			}
-		case *types.TypeName:
-			if !a.excludeTypes {
-				a.AddTypes(o)
-			}
-		default: // should never happen
-			return fmt.Errorf("unexpected object type for %s: %T", o.Name(), o)
+		if !token.IsIdentifier(t.Name) {
+			return fmt.Errorf("%w: %q for %s", ErrInvalidAliasName, t.Name, o.Name())
 		}
+		methods, err := a.addObject(o, t)
+		if err != nil {
+			return err
+		}
+		deprecated = append(deprecated, methods...)
 	}
-	return nil
+	return deprecatedError(deprecated)
+}
+
+// deprecatedError returns an error wrapping [ErrDeprecated] listing the given
+// names of the deprecated objects, or nil if there are none.
+func deprecatedError(names []string) error {
+	if len(names) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%w: %s", ErrDeprecated, strings.Join(names, ", "))
+}
+
+// addObject adds the given object, applying the given transformation, to the
+// list of its kind. It returns the deprecated methods of a type not forwarded
+// because of the [DeprecatedError] policy (see [TypeName.Methods]).
+func (a *Aliaser) addObject(o types.Object, t *Transformation) ([]string, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	switch o := o.(type) {
+	case *types.Const:
+		a.addConstant(o, t)
+	case *types.Var:
+		a.addVariable(o, t)
+	case *types.Func:
+		a.addFunction(o, t)
+	case *types.TypeName:
+		return a.addType(o, t), nil
+	default: // should never happen
+		return nil, fmt.Errorf("unexpected object type for %s: %T", o.Name(), o)
+	}
+	return nil, nil
 }
 
 // Constants returns the list of the constants loaded for aliasing.
@@ -157,18 +272,24 @@ func (a *Aliaser) Constants() []*Const {
 }
 
 // AddConstants adds the given constants to the list of the constants to
-// generate aliases for.
-func (a *Aliaser) AddConstants(cs ...*types.Const) {
+// generate aliases for. With [OnDuplicateError], it returns a
+// [*DuplicateError] if any of them has a name already in use.
+func (a *Aliaser) AddConstants(cs ...*types.Const) error {
 	a.mu.Lock()
 	defer a.mu.Unlock()
+	n := len(a.duplicates)
 	for _, c := range cs {
-		a.addConstant(c)
+		a.addConstant(c, a.transformation(c))
 	}
+	return a.duplicateError(n)
 }
 
-func (a *Aliaser) addConstant(c *types.Const) {
-	if !a.addObjectName(c, constantId) {
-		a.constants = append(a.constants, NewConst(c, a.Importer))
+func (a *Aliaser) addConstant(c *types.Const, t *Transformation) {
+	if name, skip := a.addObjectName(c, t.Name, constantId); !skip {
+		ac := NewConst(c, a.Importer)
+		ac.alias = name
+		ac.docs = a
+		a.constants = append(a.constants, ac)
 		a.AddImport(c.Pkg())
 	}
 }
@@ -181,20 +302,47 @@ func (a *Aliaser) Variables() []*Var {
 }
 
 // AddVariables adds the given variables to the list of the variables to
-// generate aliases for.
-func (a *Aliaser) AddVariables(vs ...*types.Var) {
+// generate aliases for. With [OnDuplicateError], it returns a
+// [*DuplicateError] if any of them has a name already in use.
+func (a *Aliaser) AddVariables(vs ...*types.Var) error {
 	a.mu.Lock()
 	defer a.mu.Unlock()
+	n := len(a.duplicates)
 	for _, v := range vs {
-		a.addVariable(v)
+		a.addVariable(v, a.transformation(v))
 	}
+	return a.duplicateError(n)
 }
 
-func (a *Aliaser) addVariable(v *types.Var) {
-	if !a.addObjectName(v, variableId) {
-		a.AddImport(v.Pkg())
-		a.variables = append(a.variables, NewVar(v, a.Importer))
+func (a *Aliaser) addVariable(v *types.Var, t *Transformation) {
+	if t.VarStrategy == VarAccessors && a.onDuplicate == OnDuplicateReplace {
+		// the setter cannot replace a declaration of the target package, so
+		// the getter must not replace anything either
+		if id, ok := a.names.Get("Set" + t.Name); ok && id == targetId {
+			return
+		}
+	}
+	name, skip := a.addObjectName(v, t.Name, variableId)
+	if skip {
+		return
+	}
+	var setter string
+	if t.VarStrategy == VarAccessors {
+		// the setter is declared as well, so its name is subject to the same
+		// duplicate behavior as the one of the getter
+		if setter, skip = a.addObjectName(v, "Set"+name, setterId); skip {
+			a.names.Delete(name)
+			return
+		}
 	}
+	a.AddImport(v.Pkg())
+	av := NewVar(v, a.Importer)
+	av.alias = name
+	av.setter = setter
+	av.docs = a
+	av.strategy = t.VarStrategy
+	av.locals = a.locals
+	a.variables = append(a.variables, av)
 }
 
 // Functions returns the list of the functions loaded for aliasing.
@@ -205,19 +353,27 @@ func (a *Aliaser) Functions() []*Func {
 }
 
 // AddFunctions adds the given functions to the list of the functions to
-// generate aliases for.
-func (a *Aliaser) AddFunctions(fns ...*types.Func) {
+// generate aliases for. With [OnDuplicateError], it returns a
+// [*DuplicateError] if any of them has a name already in use.
+func (a *Aliaser) AddFunctions(fns ...*types.Func) error {
 	a.mu.Lock()
 	defer a.mu.Unlock()
+	n := len(a.duplicates)
 	for _, fn := range fns {
-		a.addFunction(fn)
+		a.addFunction(fn, a.transformation(fn))
 	}
+	return a.duplicateError(n)
 }
 
-func (a *Aliaser) addFunction(fn *types.Func) {
-	if !a.addObjectName(fn, functionId) {
+func (a *Aliaser) addFunction(fn *types.Func, t *Transformation) {
+	if name, skip := a.addObjectName(fn, t.Name, functionId); !skip {
 		a.AddImport(fn.Pkg())
-		a.functions = append(a.functions, NewFunc(fn, a.Importer))
+		afn := NewFunc(fn, a.Importer)
+		afn.setAliasName(name)
+		afn.docs = a
+		afn.strategy = t.FuncStrategy
+		afn.locals, afn.tsig.locals = a.locals, a.locals
+		a.functions = append(a.functions, afn)
 	}
 }
 
@@ -229,20 +385,53 @@ func (a *Aliaser) Types() []*TypeName {
 }
 
 // AddTypes adds the given types to the list of the types to generate aliases
-// for.
-func (a *Aliaser) AddTypes(ts ...*types.TypeName) {
+// for. With [OnDuplicateError], it returns a [*DuplicateError] if any of them
+// has a name already in use. With [DeprecatedError], it returns an error
+// wrapping [ErrDeprecated] if any of the methods to forward is deprecated.
+func (a *Aliaser) AddTypes(ts ...*types.TypeName) error {
 	a.mu.Lock()
 	defer a.mu.Unlock()
+	n := len(a.duplicates)
+	var deprecated []string
 	for _, t := range ts {
-		a.addType(t)
+		deprecated = append(deprecated, a.addType(t, a.transformation(t))...)
 	}
+	return errors.Join(a.duplicateError(n), deprecatedError(deprecated))
 }
 
-func (a *Aliaser) addType(t *types.TypeName) {
-	if !a.addObjectName(t, typeId) {
-		a.AddImport(t.Pkg())
-		a.types = append(a.types, NewTypeName(t, a.Importer))
+// addType adds the given type, returning the deprecated methods not forwarded
+// because of the [DeprecatedError] policy, as "Type.Method".
+func (a *Aliaser) addType(typ *types.TypeName, t *Transformation) (deprecated []string) {
+	if name, skip := a.addObjectName(typ, t.Name, typeId); !skip {
+		a.AddImport(typ.Pkg())
+		tn := NewTypeName(typ, a.Importer)
+		tn.alias = name
+		tn.docs = a
+		tn.genericAlias = a.GenericAliases()
+		tn.locals = a.locals
+		// *types.Named or *types.Alias declared by the type name
+		if decl, ok := typ.Type().(interface{ Obj() *types.TypeName }); ok && decl.Obj() == typ && name != typ.Name() &&
+			(!tn.Generic() || tn.GenericAlias()) {
+			a.locals[typ] = newLocalType(typ.Type(), name, a.Importer)
+		}
+		if a.forwardMethods {
+			tn.forwardMethods(func(fn *types.Func) bool {
+				if a.deprecationNotice(fn) == "" {
+					return true
+				}
+				switch a.onDeprecated {
+				case DeprecatedExclude:
+					return false
+				case DeprecatedError:
+					deprecated = append(deprecated, typ.Name()+"."+fn.Name())
+					return false
+				}
+				return true
+			})
+		}
+		a.types = append(a.types, tn)
 	}
+	return deprecated
 }
 
 // objectId is the type used to identify the kind of object.
@@ -254,38 +443,104 @@ func (a *Aliaser) addType(t *types.TypeName) {
 	variableId
 	functionId
 	typeId
+	setterId // the setter of a variable with the [VarAccessors] strategy
+	targetId // declared in the target package
 )
 
-func (a *Aliaser) addObjectName(o types.Object, id objectId) (skip bool) {
-	if a.names.PutNX(o.Name(), id) {
-		return
+// addObjectName adds the given name of the alias of the given object to the
+// names declared in the generated code, applying the [OnDuplicate] behavior
+// if it is already declared. It returns the name of the alias, that may
+// differ from the given one, and whether the object must be skipped.
+func (a *Aliaser) addObjectName(o types.Object, name string, id objectId) (string, bool) {
+	if a.names.PutNX(name, id) {
+		return name, false
 	}
+	oldID, _ := a.names.Get(name)
 	switch a.onDuplicate {
 	case OnDuplicateSkip:
-		return true
+		return name, true
 	case OnDuplicateReplace:
-		oldID, ok := a.names.Swap(o.Name(), id)
-		if ok {
-			a.deleteObject(o, oldID)
+		if oldID == targetId {
+			return name, true // cannot replace a declaration of the target package
 		}
+		a.names.Put(name, id)
+		a.deleteObject(name, oldID)
 	case OnDuplicatePanic:
-		panic(fmt.Errorf("duplicate object name: %s", o.Name()))
+		panic(fmt.Errorf("duplicate object name: %s", Duplicate{name, o, oldID == targetId}))
+	case OnDuplicatePrefix, OnDuplicateRename:
+		newName := prefixedName(o.Pkg(), name)
+		if a.onDuplicate == OnDuplicateRename && a.renameDuplicate != nil {
+			if n := a.renameDuplicate(o, name); token.IsIdentifier(n) {
+				newName = n
+			}
+		}
+		for name = newName; !a.names.PutNX(name, id); {
+			name += "_"
+		}
+	case OnDuplicateError:
+		a.duplicates = append(a.duplicates, Duplicate{name, o, oldID == targetId})
+		return name, true
 	default: // should never happen, trap for development
 		panic(fmt.Errorf("unexpected OnDuplicate value: %d", a.onDuplicate))
 	}
-	return
+	return name, false
 }
 
-func (a *Aliaser) deleteObject(o types.Object, id objectId) {
+// duplicateError returns a [*DuplicateError] with the duplicates collected
+// since the given number of them, removing them from the collected ones, or
+// nil if there are none.
+func (a *Aliaser) duplicateError(since int) error {
+	if len(a.duplicates) <= since {
+		return nil
+	}
+	err := &DuplicateError{Duplicates: slices.Clone(a.duplicates[since:])}
+	a.duplicates = a.duplicates[:since]
+	return err
+}
+
+// prefixedName returns the given name prefixed with the name of the given
+// package, capitalized to keep the alias exported.
+//
+// Example:
+//
+//	errors.Error // ErrorsError
+func prefixedName(pkg *types.Package, name string) string {
+	r, size := utf8.DecodeRuneInString(pkg.Name())
+	return string(unicode.ToUpper(r)) + pkg.Name()[size:] + name
+}
+
+func (a *Aliaser) deleteObject(name string, id objectId) {
 	switch id {
 	case constantId:
-		a.constants = slices.DeleteFunc(a.constants, newObjSliceDel[*Const](o))
+		a.constants = slices.DeleteFunc(a.constants, newObjSliceDel[*Const](name))
 	case variableId:
-		a.variables = slices.DeleteFunc(a.variables, newObjSliceDel[*Var](o))
+		a.variables = slices.DeleteFunc(a.variables, func(v *Var) bool {
+			if v.AliasName() != name {
+				return false
+			}
+			if v.Accessors() {
+				a.names.Delete(v.SetterName())
+			}
+			return true
+		})
+	case setterId:
+		// the getter cannot be declared without the setter
+		a.variables = slices.DeleteFunc(a.variables, func(v *Var) bool {
+			if !v.Accessors() || v.SetterName() != name {
+				return false
+			}
+			a.names.Delete(v.AliasName())
+			return true
+		})
 	case functionId:
-		a.functions = slices.DeleteFunc(a.functions, newObjSliceDel[*Func](o))
+		a.functions = slices.DeleteFunc(a.functions, newObjSliceDel[*Func](name))
 	case typeId:
-		a.types = slices.DeleteFunc(a.types, newObjSliceDel[*TypeName](o))
+		for _, tn := range a.types {
+			if tn.AliasName() == name {
+				delete(a.locals, tn.TypeName)
+			}
+		}
+		a.types = slices.DeleteFunc(a.types, newObjSliceDel[*TypeName](name))
 	default: // should never happen, trap for development
 		panic(fmt.Errorf("unexpected object ID: %d", id))
 	}
@@ -293,15 +548,52 @@ func (a *Aliaser) deleteObject(o types.Object, id objectId) {
 
 // newObjSliceDel returns a function, compatible with the signature of the
 // [slices.DeleteFunc], that returns true if the given object (the one that
-// will be deleted) has the same name of the one used to create the function.
-func newObjSliceDel[O types.Object](obj types.Object) func(O) bool {
+// will be deleted) has the alias name used to create the function.
+func newObjSliceDel[O interface{ AliasName() string }](name string) func(O) bool {
 	return func(o O) bool {
-		return obj.Name() == o.Name()
+		return name == o.AliasName()
 	}
 }
 
+// aliasNameOf returns the name of the alias of the given object, if any.
+// It must be called with the lock held.
+func (a *Aliaser) aliasNameOf(obj types.Object) (string, bool) {
+	var objs []interface {
+		Object
+		AliasName() string
+	}
+	switch obj.(type) {
+	case *types.Const:
+		for _, c := range a.constants {
+			objs = append(objs, c)
+		}
+	case *types.Var:
+		for _, v := range a.variables {
+			objs = append(objs, v)
+		}
+	case *types.Func:
+		for _, fn := range a.functions {
+			objs = append(objs, fn)
+		}
+	case *types.TypeName:
+		for _, tn := range a.types {
+			objs = append(objs, tn)
+		}
+	}
+	for _, o := range objs {
+		if o.Pos() == obj.Pos() && o.Name() == obj.Name() && o.Pkg() == obj.Pkg() {
+			return o.AliasName(), true
+		}
+	}
+	return "", false
+}
+
 // Generate writes the aliases to the given writer.
 //
+// If target platforms are set (see [WithPlatforms]), only the aliases of the
+// objects declared on all of them are written. Use [Aliaser.GenerateFile] to
+// generate also the platform specific files.
+//
 // Generate returns an error if fails to execute the template, format the
 // generated code, or write the result to the writer.
 func (a *Aliaser) Generate(wr io.Writer) error {
@@ -312,31 +604,100 @@ func (a *Aliaser) Generate(wr io.Writer) error {
 
 // GenerateFile behaves like [Aliaser.Generate], but it writes the aliases to
 // the file with the given name creating the necessary directories. If the file
-// already exists, it is truncated.
+// already exists, it is replaced atomically (see [WriteFileAtomic]), unless
+// the legacy in-place mode is enabled (see [InPlaceWrites]). If its content is
+// the same as the generated one, the file is left untouched, so that its
+// modification time does not change.
+//
+// If target platforms are set (see [WithPlatforms]), the aliases of the
+// objects declared only on some of them are written to the files named by
+// [PlatformFileName] in the same directory, each one guarded by the build
+// constraint satisfied by those platforms.
 //
 // GenerateFile returns an error in the same cases as [Aliaser.Generate] and
 // if any of the directory creation or file writing operations fail. In this
-// case, if the file did not exist before the operation, it is removed,
-// otherwise, its content is reset to the original state.
+// case, the file is left untouched. The files generated before the failing
+// one are kept.
 func (a *Aliaser) GenerateFile(name string) error {
+	_, err := a.GenerateFiles(name)
+	return err
+}
+
+// GeneratedFile is a file written by [Aliaser.GenerateFiles].
+type GeneratedFile struct {
+	// Name is the name of the file.
+	Name string
+
+	// Changed reports whether the file has been written, since it did not
+	// exist or its content differed from the generated one.
+	Changed bool
+}
+
+// GenerateFiles behaves like [Aliaser.GenerateFile], but it also returns the
+// generated files, in the order they were written, reporting whether each of
+// them has changed. In case of error, the files generated before the failing
+// one are returned.
+//
+// Example:
+//
+//	files, err := a.GenerateFiles("mypkg/alias.go")
+//	if err != nil {
+//		// ...
+//	}
+//	for _, f := range files {
+//		fmt.Println(f.Name, f.Changed)
+//	}
+func (a *Aliaser) GenerateFiles(name string) ([]GeneratedFile, error) {
+	return a.GenerateFS(OSFS{InPlace: a.inPlaceWrites}, name)
+}
+
+// GenerateFS behaves like [Aliaser.GenerateFiles], but it writes the files
+// to the given file system, e.g. an in-memory one ([MemFS]) or a staging
+// directory ([OSFS]), instead of the operating system one.
+//
+// Example:
+//
+//	var mfs aliaser.MemFS
+//	if _, err := a.GenerateFS(&mfs, "mypkg/alias.go"); err != nil {
+//		// ...
+//	}
+//	data, err := mfs.ReadFile("mypkg/alias.go")
+func (a *Aliaser) GenerateFS(fsys WritableFS, name string) ([]GeneratedFile, error) {
 	a.mu.RLock()
 	defer a.mu.RUnlock()
-	if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
-		return fmt.Errorf("create directory: %w", err)
-	}
-	f, reset, err := OpenFileWithReset(name)
+	var files []GeneratedFile
+	gf, err := a.generateFile(fsys, name)
 	if err != nil {
-		return err
+		return files, err
 	}
-	defer f.Close()
-	if err := a.generate(f); err != nil {
-		err = fmt.Errorf("generate: %w", err)
-		if rerr := reset(); rerr != nil {
-			return errors.Join(err, fmt.Errorf("reset file: %w", rerr))
+	files = append(files, gf)
+	for _, pf := range a.platformFiles {
+		gf, err := pf.view.generateFile(fsys, PlatformFileName(name, pf.platforms...))
+		if err != nil {
+			return files, err
 		}
-		return err
+		files = append(files, gf)
 	}
-	return nil
+	return files, nil
+}
+
+func (a *Aliaser) generateFile(fsys WritableFS, name string) (GeneratedFile, error) {
+	gf := GeneratedFile{Name: name}
+	buf := new(bytes.Buffer)
+	if err := a.generate(buf); err != nil {
+		return gf, fmt.Errorf("generate: %w", err)
+	}
+	if data, err := fsys.ReadFile(name); err == nil && bytes.Equal(data, buf.Bytes()) {
+		return gf, nil
+	}
+	if err := fsys.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+		return gf, fmt.Errorf("create directory: %w", err)
+	}
+	if err := fsys.WriteFile(name, buf.Bytes(), 0o644); err != nil {
+		return gf, fmt.Errorf("write: %w", err)
+	}
+	gf.Changed = true
+	return gf, nil
 }
 
 func (a *Aliaser) generate(wr io.Writer) error {
@@ -377,13 +738,32 @@ type Config struct {
 	TargetPackage string
 
 	// [REQUIRED]
-	// Pattern is the package pattern in Go format to be loaded.
+	// Pattern is the package pattern in Go format to be loaded. It may be
+	// omitted if [Config.Patterns] is not empty.
+	//
+	// If it matches more than one package, e.g. "github.com/example/foo/...",
+	// their objects are merged into the target package only if the merging
+	// is enabled (see [MergePackages]).
 	//
 	// Example:
 	//
 	//	"github.com/marcozac/go-aliaser/pkg-that-will-be-aliased"
 	Pattern string
 
+	// Patterns is an optional list of additional package patterns to be
+	// loaded along with [Config.Pattern]. If the patterns are more than one,
+	// the objects of all the loaded packages are merged into the target
+	// package, resolving the name conflicts according to the [OnDuplicate]
+	// behavior (e.g. [OnDuplicatePrefix]).
+	//
+	// Example:
+	//
+	//	[]string{
+	//		"github.com/example/foo/api",
+	//		"github.com/example/foo/types",
+	//	}
+	Patterns []string
+
 	// Header is an optional header to be written at the top of the file.
 	//
 	// Default: "// Code generated by aliaser. DO NOT EDIT."
@@ -391,11 +771,74 @@ type Config struct {
 
 	// AssignFunctions sets whether the aliases for the functions should be
 	// assigned to a variable instead of being wrapped.
+	//
+	// Since a generic function cannot be used without instantiation, the
+	// generic functions are always wrapped (see [Func.Generic]).
 	AssignFunctions bool
+
+	// GoVersion is the Go version of the module where the aliases will be
+	// generated, as declared by its go directive (e.g. "1.24.0"). It is used
+	// to determine which language features the generated code can use.
+	//
+	// Default: the version declared in the go.mod file of the module that
+	// contains the directory set by [WithDir], or the current working
+	// directory if not set.
+	GoVersion string
+}
+
+// GenericAliases reports whether the generic types can be aliased using
+// parameterized type aliases. It is true if [Config.GoVersion] is at least
+// [GenericAliasVersion]. Otherwise, the aliases of the generic types are
+// generated as new defined types, losing the methods of the original ones.
+func (c *Config) GenericAliases() bool {
+	return goVersionAtLeast(c.GoVersion, GenericAliasVersion)
+}
+
+// BuildConstraint returns the build constraint of the generated file, if any.
+// It is set only for the files of the objects declared on some of the target
+// platforms (see [WithPlatforms]).
+func (c *Config) BuildConstraint() string {
+	return c.buildConstraint
+}
+
+// packagesConfig returns the configuration used to load the packages with
+// the given mode, applying the loader options (e.g. [WithDir]) and the
+// environment and build flags of the platform being loaded, if any.
+func (c *Config) packagesConfig(mode packages.LoadMode) *packages.Config {
+	pc := &packages.Config{
+		Mode:       mode,
+		Context:    c.ctx,
+		Dir:        c.dir,
+		BuildFlags: slices.Clone(c.buildFlags),
+		Overlay:    c.overlay,
+	}
+	if len(c.env) > 0 {
+		pc.Env = append(os.Environ(), c.env...)
+	}
+	if p := c.platform; p != nil {
+		if pc.Env == nil {
+			pc.Env = os.Environ()
+		}
+		pc.Env = append(pc.Env, p.env()...)
+		pc.BuildFlags = append(pc.BuildFlags, p.buildFlags()...)
+	}
+	return pc
+}
+
+// patterns returns the non-empty patterns of the configuration.
+func (c *Config) patterns() []string {
+	patterns := make([]string, 0, len(c.Patterns)+1)
+	for _, p := range append([]string{c.Pattern}, c.Patterns...) {
+		if p != "" {
+			patterns = append(patterns, p)
+		}
+	}
+	return patterns
 }
 
 func (c *Config) setDefaults() *Config {
 	c.excludedNames = make(map[string]struct{})
+	c.varStrategies = make(map[string]VarStrategy)
 	if c.Header == "" {
 		c.Header = "// Code generated by aliaser. DO NOT EDIT."
 	}
@@ -419,7 +862,85 @@ type config struct {
 	excludeFunctions bool
 	excludeTypes     bool
 	excludedNames    map[string]struct{}
-	onDuplicate      int
+	onDuplicate      DuplicatePolicy
+	renameDuplicate  func(types.Object, string) string
+	forwardMethods   bool
+	varStrategy      VarStrategy
+	varStrategies    map[string]VarStrategy
+	rewriteDocLinks  bool
+	onDeprecated     DeprecatedPolicy
+	mergePackages    bool
+	platforms        []Platform
+	platform         *Platform
+	buildConstraint  string
+	dir              string
+	env              []string
+	buildFlags       []string
+	overlay          map[string][]byte
+	includes         []nameFilter
+	excludes         []nameFilter
+	filterErr        error
+	filters          []func(types.Object) bool
+	transformers     []Transformer
+	renamer          renamer
+	targetDir        string
+	importStrategy   importer.Strategy
+	importAliases    map[string]string
+	reservedNames    []string
+	inPlaceWrites    bool
+}
+
+// newImporter returns a new [importer.Importer] using the import strategy,
+// restoring the import aliases and reserving the names of the configuration.
+func (c *config) newImporter() *importer.Importer {
+	imp := importer.NewWithStrategy(c.importStrategy)
+	if len(c.importAliases) > 0 {
+		imp.Restore(c.importAliases)
+	}
+	imp.Reserve(c.reservedNames...)
+	return imp
+}
+
+// excluded reports whether the given object is excluded by name, by kind or
+// by the name filters (see [IncludeMatching] and [ExcludeMatching]) or by any
+// of the predicate filters (see [WithFilter]).
+func (c *config) excluded(o types.Object) bool {
+	if _, ok := c.excludedNames[o.Name()]; ok {
+		return true
+	}
+	switch o.(type) {
+	case *types.Const:
+		if c.excludeConstants {
+			return true
+		}
+	case *types.Var:
+		if c.excludeVariables {
+			return true
+		}
+	case *types.Func:
+		if c.excludeFunctions {
+			return true
+		}
+	case *types.TypeName:
+		if c.excludeTypes {
+			return true
+		}
+	}
+	for _, keep := range c.filters {
+		if !keep(o) {
+			return true
+		}
+	}
+	return !c.included(o)
+}
+
+// varStrategyOf returns the strategy to use for the variable with the given
+// name: the one set for the name, if any, or the default one.
+func (c *config) varStrategyOf(name string) VarStrategy {
+	if s, ok := c.varStrategies[name]; ok {
+		return s
+	}
+	return c.varStrategy
 }
 
 // Option is the interface implemented by all options.
@@ -438,6 +959,65 @@ func WithContext(ctx context.Context) Option {
 	})
 }
 
+// WithDir sets the directory in which the build system is run to load the
+// packages, so that the patterns are resolved relative to it and the module
+// containing it is used. It is also used to find the Go version of the
+// generated code if [Config.GoVersion] is not set.
+//
+// Default: the current working directory.
+func WithDir(dir string) Option {
+	return option(func(c *Config) {
+		c.dir = dir
+	})
+}
+
+// WithEnv adds the given variables, in the form "KEY=VALUE", to the
+// environment of the build system used to load the packages. They override
+// the ones of the current process with the same key.
+//
+// Example:
+//
+//	aliaser.WithEnv("GOFLAGS=-mod=vendor", "CGO_ENABLED=0")
+func WithEnv(env ...string) Option {
+	return option(func(c *Config) {
+		c.env = append(c.env, env...)
+	})
+}
+
+// WithBuildFlags adds the given flags to the command line of the build system
+// used to load the packages. The build tags of the target platforms, if any,
+// are passed after them (see [WithPlatforms]).
+//
+// Example:
+//
+//	aliaser.WithBuildFlags("-tags=integration", "-mod=mod")
+func WithBuildFlags(flags ...string) Option {
+	return option(func(c *Config) {
+		c.buildFlags = append(c.buildFlags, flags...)
+	})
+}
+
+// WithOverlay sets the contents of the given files, replacing the ones on
+// disk, when loading the packages. The keys are the absolute paths of the
+// files, which may also not exist on disk. See [packages.Config.Overlay] for
+// more details.
+//
+// Example:
+//
+//	aliaser.WithOverlay(map[string][]byte{
+//		"/path/to/pkg/gen.go": []byte("package pkg\n\nconst Version = \"v1.0.0\"\n"),
+//	})
+func WithOverlay(overlay map[string][]byte) Option {
+	return option(func(c *Config) {
+		if c.overlay == nil {
+			c.overlay = make(map[string][]byte, len(overlay))
+		}
+		for name, data := range overlay {
+			c.overlay[name] = data
+		}
+	})
+}
+
 // WithHeader sets an optional header to be written at the top of the file.
 func WithHeader(header string) Option {
 	return option(func(c *Config) {
@@ -445,6 +1025,79 @@ func WithHeader(header string) Option {
 	})
 }
 
+// WithImportStrategy sets the strategy used to choose the names of the
+// imported packages in the generated code. The default is [importer.AliasAll],
+// that declares an explicit name for every import.
+//
+// Example:
+//
+//	// import (
+//	//	"bytes"
+//	//	stdjson "encoding/json"
+//	//	"example.com/json"
+//	// )
+//	aliaser.WithImportStrategy(importer.AliasPinned{"encoding/json": "stdjson"})
+func WithImportStrategy(s importer.Strategy) Option {
+	return option(func(c *Config) {
+		c.importStrategy = s
+	})
+}
+
+// WithImportAliases sets the aliases of the imported packages, formatted as
+// "path:alias", usually those of the code generated by a previous run (see
+// [importer.ParseAliases]). The packages with a path in the map keep the
+// given alias, while the others are given a name that does not conflict with
+// them, so that the generated code changes as little as possible when a
+// package with a conflicting name is imported (see [importer.Importer.Restore]).
+//
+// Example:
+//
+//	aliases, err := importer.ParseAliases("mypkg/alias.go", nil)
+//	if err != nil {
+//		// ...
+//	}
+//	aliaser.WithImportAliases(aliases)
+func WithImportAliases(aliases map[string]string) Option {
+	return option(func(c *Config) {
+		c.importAliases = aliases
+	})
+}
+
+// ReserveNames reserves the given names, so that they are never used as
+// aliases of the imported packages (see [importer.Importer.Reserve]), e.g.
+// the names declared in other files of the target package, if not collected
+// by [WithTargetDir]. The Go keywords, the predeclared identifiers and the
+// names declared by the generated aliases are always reserved.
+//
+// Example:
+//
+//	// import errors_2 "errors"
+//	aliaser.ReserveNames("errors")
+func ReserveNames(names ...string) Option {
+	return option(func(c *Config) {
+		c.reservedNames = append(c.reservedNames, names...)
+	})
+}
+
+// InPlaceWrites enables the legacy mode of [Aliaser.GenerateFile], that opens
+// the existing files in place with [OpenFileWithReset] and truncates them,
+// restoring the original content if the generation fails, instead of writing
+// them atomically through a temporary file. Unlike the default mode, the
+// files may be left truncated if the process is killed while writing.
+func InPlaceWrites(enable bool) Option {
+	return option(func(c *Config) {
+		c.inPlaceWrites = enable
+	})
+}
+
+// WithGoVersion sets the Go version of the module where the aliases will be
+// generated. See [Config.GoVersion] for more details.
+func WithGoVersion(v string) Option {
+	return option(func(c *Config) {
+		c.GoVersion = v
+	})
+}
+
 // ExcludeConstants excludes the constants from the loaded package.
 func ExcludeConstants(v bool) Option {
 	return option(func(c *Config) {
@@ -488,29 +1141,145 @@ func ExcludeNames(names ...string) Option {
 }
 
 // AssignFunctions sets whether the aliases for the functions should be
-// assigned to a variable instead of being wrapped.
+// assigned to a variable instead of being wrapped. The generic functions are
+// always wrapped, since they cannot be assigned without instantiation.
+//
+// Example:
+//
+//	var (
+//		Foo = pkg.Foo
+//	)
+//
+//	func Bar[T any](v T) T {
+//		return pkg.Bar[T](v)
+//	}
 func AssignFunctions(v bool) Option {
 	return option(func(c *Config) {
 		c.AssignFunctions = v
 	})
 }
 
-const (
-	// OnDuplicateSkip is the default behavior when a duplicate object name is
-	// found. It skips the object and does not generate an alias for it.
-	OnDuplicateSkip = iota
+// ForwardMethods sets whether the methods of the generic types that cannot be
+// aliased using a parameterized type alias (see [Config.GenericAliases]) should
+// be forwarded to the original type.
+//
+// In this case, the alias is declared as a new defined type that does not
+// inherit the methods of the original one. Enabling this option, a forwarding
+// method is generated for each exported method of the original type,
+// converting the receiver and any parameter or result of the receiver type to
+// and from the original type.
+//
+// Example:
+//
+//	type P[T any] pkg.P[T]
+//
+//	func (r *P[T]) Foo(v T) {
+//		(*pkg.P[T])(r).Foo(v)
+//	}
+func ForwardMethods(v bool) Option {
+	return option(func(c *Config) {
+		c.forwardMethods = v
+	})
+}
+
+// WithVarStrategy sets the strategy used to generate the aliases of the
+// variables. If no names are given, the strategy is used for all the variables
+// without a specific one. Otherwise, it is used only for the variables with
+// the given names. See [VarStrategy] for the available strategies.
+//
+// Example:
+//
+//	// Generate getter and setter functions for all the variables, but use a
+//	// pointer for DefaultClient.
+//	aliaser.WithVarStrategy(aliaser.VarAccessors)
+//	aliaser.WithVarStrategy(aliaser.VarPointer, "DefaultClient")
+func WithVarStrategy(s VarStrategy, names ...string) Option {
+	return option(func(c *Config) {
+		if len(names) == 0 {
+			c.varStrategy = s
+			return
+		}
+		for _, n := range names {
+			c.varStrategies[n] = s
+		}
+	})
+}
+
+// RewriteDocLinks sets whether the doc links in the doc comments copied from
+// the loaded package should be rewritten to point to the generated aliases.
+//
+// The links to symbols of the loaded package are left unchanged if they have
+// an alias, otherwise they are qualified with the package alias used in the
+// generated code. The links to other packages are rewritten to use the
+// package aliases of the generated code.
+//
+// Example, with Baz excluded from the aliases:
+//
+//	// Foo returns a [Bar] encoded as [json.Marshaler] (see [Baz]).
+//	// becomes
+//	// Foo returns a [Bar] encoded as [json_2.Marshaler] (see [pkg.Baz]).
+func RewriteDocLinks(v bool) Option {
+	return option(func(c *Config) {
+		c.rewriteDocLinks = v
+	})
+}
 
-	// OnDuplicateReplace replaces the old object (even if it is a different
-	// kind of object) with the new one.
-	OnDuplicateReplace
+// OnDeprecated sets the policy applied to the objects of the loaded package
+// marked as deprecated. See [DeprecatedPolicy] for the available policies.
+func OnDeprecated(p DeprecatedPolicy) Option {
+	return option(func(c *Config) {
+		c.onDeprecated = p
+	})
+}
 
-	// OnDuplicatePanic panics when a duplicate object name is found.
-	OnDuplicatePanic
-)
+// MergePackages sets whether the objects of all the packages matched by
+// [Config.Pattern] should be merged into the target package. Otherwise, the
+// pattern must match exactly one package. The name conflicts are resolved
+// according to the [OnDuplicate] behavior (e.g. [OnDuplicatePrefix]), in
+// the order of the package paths.
+//
+// The merging is always enabled if more than one pattern is given (see
+// [Config.Patterns]).
+//
+// Example:
+//
+//	aliaser.New(&aliaser.Config{
+//		TargetPackage: "foo",
+//		Pattern:       "github.com/example/foo/...",
+//	}, aliaser.MergePackages(true), aliaser.OnDuplicate(aliaser.OnDuplicatePrefix))
+func MergePackages(v bool) Option {
+	return option(func(c *Config) {
+		c.mergePackages = v
+	})
+}
 
-// OnDuplicate sets the behavior when a duplicate object name is found.
-func OnDuplicate(v int) Option {
+// OnDuplicate sets the behavior when a duplicate object name is found (see
+// [DuplicatePolicy]). The default is [OnDuplicateSkip].
+func OnDuplicate(v DuplicatePolicy) Option {
 	return option(func(c *Config) {
 		c.onDuplicate = v
 	})
 }
+
+// RenameDuplicates sets the function used by [OnDuplicateRename] to rename
+// the alias of an object whose name is already in use. It receives the
+// object and the name of its alias and must return a valid identifier,
+// otherwise the alias is prefixed with the capitalized name of the package of
+// the object, as with [OnDuplicatePrefix].
+//
+// Example:
+//
+//	// Error = api.Error
+//	// ErrorsError = errors.Error
+//	aliaser.OnDuplicate(aliaser.OnDuplicateRename)
+//	aliaser.RenameDuplicates(func(obj types.Object, name string) string {
+//		return name + "Of" + strings.ToUpper(obj.Pkg().Name())
+//	})
+//
+//	// Error = api.Error
+//	// ErrorOfERRORS = errors.Error
+func RenameDuplicates(fn func(obj types.Object, name string) string) Option {
+	return option(func(c *Config) {
+		c.renameDuplicate = fn
+	})
+}
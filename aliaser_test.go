@@ -4,13 +4,17 @@
 	"bytes"
 	"context"
 	"embed"
+	"fmt"
 	"go/types"
 	"io"
 	"os"
 	"path/filepath"
 	"slices"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/marcozac/go-aliaser/importer"
 	"github.com/marcozac/go-aliaser/util/sequence"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -26,6 +30,42 @@ func TestAliaser(t *testing.T) {
 			assert.NoError(t, a.addPkgObjects(p)) // cover not exported object
 		})
 	}))
+	t.Run("GenerateFiles", AliaserTest(func(t *testing.T, a *Aliaser) {
+		name := filepath.Join(t.TempDir(), "out", "alias.go")
+		files, err := a.GenerateFiles(name)
+		require.NoError(t, err)
+		assert.Equal(t, []GeneratedFile{{Name: name, Changed: true}}, files)
+
+		past := time.Now().Add(-time.Hour).Truncate(time.Second)
+		require.NoError(t, os.Chtimes(name, past, past))
+		files, err = a.GenerateFiles(name)
+		require.NoError(t, err)
+		assert.Equal(t, []GeneratedFile{{Name: name, Changed: false}}, files)
+		fi, err := os.Stat(name)
+		require.NoError(t, err)
+		assert.Equal(t, past, fi.ModTime(), "unchanged file not rewritten")
+
+		require.NoError(t, os.WriteFile(name, []byte("package foo\n"), 0o644))
+		files, err = a.GenerateFiles(name)
+		require.NoError(t, err)
+		assert.Equal(t, []GeneratedFile{{Name: name, Changed: true}}, files)
+	}))
+	t.Run("GenerateFS", AliaserTest(func(t *testing.T, a *Aliaser) {
+		var mfs MemFS
+		files, err := a.GenerateFS(&mfs, "foo/alias.go")
+		require.NoError(t, err)
+		assert.Equal(t, []GeneratedFile{{Name: "foo/alias.go", Changed: true}}, files)
+		var buf bytes.Buffer
+		require.NoError(t, a.Generate(&buf))
+		data, err := mfs.ReadFile("foo/alias.go")
+		require.NoError(t, err)
+		assert.Equal(t, buf.String(), string(data))
+		files, err = a.GenerateFS(&mfs, "foo/alias.go")
+		require.NoError(t, err)
+		assert.Equal(t, []GeneratedFile{{Name: "foo/alias.go", Changed: false}}, files)
+		_, err = a.GenerateFS(&mfs, "foo/alias.go/alias.go")
+		assert.Error(t, err, "mkdir")
+	}))
 }
 
 func TestAliaserOptions(t *testing.T) {
@@ -70,13 +110,210 @@ func TestAliaserOptions(t *testing.T) {
 			var buf bytes.Buffer
 			require.NoError(t, a.Generate(&buf))
 			assert.NotContains(t, buf.String(), "func J(")
+			assert.Contains(t, buf.String(), "J = pkg.J")
+			assert.Contains(t, buf.String(), "W = pkg.W")
+			// generic functions are always wrapped
+			assert.NotContains(t, buf.String(), "S = pkg.S")
+			assert.Contains(t, buf.String(), "func S[T any](t T) {")
+			assert.Contains(t, buf.String(), "return pkg.U[T]()")
 		}, AssignFunctions(true)))
+		t.Run("OnlyGeneric", AliaserTest(func(t *testing.T, a *Aliaser) {
+			var buf bytes.Buffer
+			require.NoError(t, a.Generate(&buf))
+			assert.NotContains(t, buf.String(), "// Functions")
+			assert.Contains(t, buf.String(), "func S[T any](t T) {")
+		}, AssignFunctions(true), ExcludeNames("Blank", "C", "J", "W", "Z")))
 		t.Run("False", AliaserTest(func(t *testing.T, a *Aliaser) {
 			var buf bytes.Buffer
 			require.NoError(t, a.Generate(&buf))
 			assert.Contains(t, buf.String(), "func J(")
 		}, AssignFunctions(false)))
 	})
+	t.Run("GoVersion", func(t *testing.T) {
+		t.Run("Default", AliaserTest(func(t *testing.T, a *Aliaser) {
+			assert.Equal(t, "1.22.0", a.GoVersion)
+			assert.False(t, a.GenericAliases())
+		}))
+		t.Run("GenericAliases", AliaserTest(func(t *testing.T, a *Aliaser) {
+			assert.True(t, a.GenericAliases())
+			var buf bytes.Buffer
+			require.NoError(t, a.Generate(&buf))
+			assert.Contains(t, buf.String(), "N[T any] = pkg.N[T]")
+			assert.Contains(t, buf.String(), "P[T any, V ~string] = pkg.P[T, V]")
+			assert.Contains(t, buf.String(), "O = pkg.O")
+		}, WithGoVersion("1.24")))
+		t.Run("DefinedTypes", AliaserTest(func(t *testing.T, a *Aliaser) {
+			assert.False(t, a.GenericAliases())
+			var buf bytes.Buffer
+			require.NoError(t, a.Generate(&buf))
+			assert.Contains(t, buf.String(), "N[T any] pkg.N[T]")
+			assert.Contains(t, buf.String(), "P[T any, V ~string] pkg.P[T, V]")
+		}, WithGoVersion("1.23")))
+	})
+	t.Run("ForwardMethods", func(t *testing.T) {
+		t.Run("True", AliaserTest(func(t *testing.T, a *Aliaser) {
+			var buf bytes.Buffer
+			require.NoError(t, a.Generate(&buf))
+			assert.Contains(t, buf.String(), "func (r *P[T, V]) Foo() {")
+			assert.Contains(t, buf.String(), "return pkg.P[T, V](r).Bar(v)")
+			assert.Contains(t, buf.String(), "func (r *P[T, V]) Baz(other P[T, V], pkg_ ...string) *P[T, V] {")
+			assert.Contains(t, buf.String(), "r0 := (*pkg.P[T, V])(r).Baz(pkg.P[T, V](other), pkg_...)")
+			assert.Contains(t, buf.String(), "return (*P[T, V])(r0)")
+		}, ForwardMethods(true)))
+		t.Run("GenericAliases", AliaserTest(func(t *testing.T, a *Aliaser) {
+			for _, tn := range a.Types() {
+				assert.Empty(t, tn.Methods())
+			}
+		}, ForwardMethods(true), WithGoVersion("1.24")))
+		t.Run("UnnamedParams", AliaserTest(func(t *testing.T, a *Aliaser) {
+			var buf bytes.Buffer
+			require.NoError(t, a.Generate(&buf))
+			assert.Contains(t, buf.String(), "func (r P[T, V]) Qux(p0 T, p1 V) {\n\tpkg.P[T, V](r).Qux(p0, p1)\n}")
+		}, ForwardMethods(true)))
+		t.Run("False", AliaserTest(func(t *testing.T, a *Aliaser) {
+			var buf bytes.Buffer
+			require.NoError(t, a.Generate(&buf))
+			assert.NotContains(t, buf.String(), "func (r *P[T, V]) Foo() {")
+		}))
+	})
+	t.Run("WithVarStrategy", func(t *testing.T) {
+		t.Run("Default", AliaserTest(func(t *testing.T, a *Aliaser) {
+			for _, v := range a.Variables() {
+				assert.Equal(t, VarCopy, v.Strategy())
+			}
+		}))
+		t.Run("Global", AliaserTest(func(t *testing.T, a *Aliaser) {
+			for _, v := range a.Variables() {
+				assert.Equal(t, VarPointer, v.Strategy())
+			}
+			var buf bytes.Buffer
+			require.NoError(t, a.Generate(&buf))
+			assert.Contains(t, buf.String(), "B = &pkg.B")
+			assert.Contains(t, buf.String(), "I = &pkg.I")
+		}, WithVarStrategy(VarPointer)))
+		t.Run("Names", AliaserTest(func(t *testing.T, a *Aliaser) {
+			var buf bytes.Buffer
+			require.NoError(t, a.Generate(&buf))
+			assert.Contains(t, buf.String(), "B = pkg.B")
+			assert.Contains(t, buf.String(), "I = pkg.I")
+			assert.Contains(t, buf.String(), "Ptr = &pkg.Ptr")
+			assert.Contains(t, buf.String(), "func X() []string {\n\treturn pkg.X\n}")
+			assert.Contains(t, buf.String(), "func SetX(v []string) {\n\tpkg.X = v\n}")
+		}, WithVarStrategy(VarPointer, "Ptr"), WithVarStrategy(VarAccessors, "X")))
+		t.Run("OnlyAccessors", AliaserTest(func(t *testing.T, a *Aliaser) {
+			var buf bytes.Buffer
+			require.NoError(t, a.Generate(&buf))
+			assert.NotContains(t, buf.String(), "var (")
+			assert.Contains(t, buf.String(), "func SetB(v string) {")
+		}, WithVarStrategy(VarAccessors)))
+	})
+	t.Run("Docs", AliaserTest(func(t *testing.T, a *Aliaser) {
+		var buf bytes.Buffer
+		require.NoError(t, a.Generate(&buf))
+		assert.Contains(t, buf.String(), "\t// A is an exported constant.\n\tA = pkg.A")
+		assert.Contains(t, buf.String(), "// X is a mutable variable.\nfunc X() []string {")
+		assert.Contains(t, buf.String(), "// SetX sets the value of [pkg.X].")
+		assert.Contains(t, buf.String(), "// C does nothing. See [D], [*E], [K.Baz], [N], [json.Foo], [stdjson] and\n"+
+			"// [stdjson.Marshaler].\n//\n// Multiline doc comment with a code block:\n//\n//\tC()\nfunc C() {")
+		assert.Contains(t, buf.String(), "\t// D is a string.\n\tD = pkg.D")
+		assert.Contains(t, buf.String(), "// Foo does nothing.\nfunc (r *P[T, V]) Foo() {")
+	}, ForwardMethods(true), WithVarStrategy(VarAccessors, "X")))
+	t.Run("RewriteDocLinks", AliaserTest(func(t *testing.T, a *Aliaser) {
+		var buf bytes.Buffer
+		require.NoError(t, a.Generate(&buf))
+		assert.Contains(t, buf.String(), "// C does nothing. See [pkg.D], [*E], [K.Baz], [N], [json_2.Foo], [json] and\n"+
+			"// [json.Marshaler].")
+	}, RewriteDocLinks(true), ExcludeNames("D")))
+	t.Run("BlankParams", AliaserTest(func(t *testing.T, a *Aliaser) {
+		var buf bytes.Buffer
+		require.NoError(t, a.Generate(&buf))
+		assert.Contains(t, buf.String(), "func Blank(p0 context.Context, p1_ int, p1 string) int {\n\treturn pkg.Blank(p0, p1_, p1)\n}")
+	}))
+	t.Run("Shadowing", AliaserTest(func(t *testing.T, a *Aliaser) {
+		var buf bytes.Buffer
+		require.NoError(t, a.Generate(&buf))
+		assert.Contains(t, buf.String(), "func Shadow[pkg_ any, json_ ~string](context_ pkg_, pkg__ json_, json__ ...pkg_) (pkg_, json_) {\n"+
+			"\treturn pkg.Shadow[pkg_, json_](context_, pkg__, json__...)\n}")
+		assert.Contains(t, buf.String(), "func T[C context.Context, S ~string, T_ any](ctx C, s S, t T_) (S, *pkg.P[T_, S]) {")
+		assert.Contains(t, buf.String(), "Shadowed[pkg_ any] pkg.Shadowed[pkg_]")
+		assert.Contains(t, buf.String(), "func (r Shadowed[pkg_]) Get(json_ pkg_) pkg_ {")
+		assert.Contains(t, buf.String(), "func (r *Shadowed[pkg_]) Set(Shadowed_ pkg_) *Shadowed[pkg_] {")
+	}, ForwardMethods(true)))
+	t.Run("OnDeprecated", func(t *testing.T) {
+		t.Run("Propagate", AliaserTest(func(t *testing.T, a *Aliaser) {
+			var buf bytes.Buffer
+			require.NoError(t, a.Generate(&buf))
+			assert.Contains(t, buf.String(), "// Deprecated: use [C] instead.\nfunc Z() {")
+			assert.Contains(t, buf.String(), "// Deprecated: use [X] instead.\nfunc Y() int {")
+			assert.Contains(t, buf.String(), "// SetY sets the value of [pkg.Y].\n//\n// Deprecated: use [X] instead.\nfunc SetY(")
+			assert.Contains(t, buf.String(), "// Deprecated: use [P.Foo] instead.\nfunc (r *P[T, V]) Old() {")
+		}, WithVarStrategy(VarAccessors, "Y"), ForwardMethods(true)))
+		t.Run("Exclude", AliaserTest(func(t *testing.T, a *Aliaser) {
+			assert.False(t, slices.ContainsFunc(a.Variables(), func(v *Var) bool { return v.Name() == "Y" }))
+			assert.False(t, slices.ContainsFunc(a.Functions(), func(fn *Func) bool { return fn.Name() == "Z" }))
+			assert.True(t, slices.ContainsFunc(a.Functions(), func(fn *Func) bool { return fn.Name() == "C" }))
+			i := slices.IndexFunc(a.Types(), func(tn *TypeName) bool { return tn.Name() == "P" })
+			require.GreaterOrEqual(t, i, 0)
+			assert.NotEmpty(t, a.Types()[i].Methods())
+			assert.False(t, slices.ContainsFunc(a.Types()[i].Methods(), func(m *Method) bool { return m.Name() == "Old" }))
+		}, OnDeprecated(DeprecatedExclude), ForwardMethods(true)))
+		t.Run("Error", func(t *testing.T) {
+			_, err := New(&Config{TargetPackage: TestTarget, Pattern: TestPattern}, OnDeprecated(DeprecatedError))
+			assert.ErrorIs(t, err, ErrDeprecated)
+			assert.ErrorContains(t, err, "Y, Z")
+			t.Run("Excluded", AliaserTest(func(t *testing.T, a *Aliaser) {
+				assert.NotEmpty(t, a.Functions())
+			}, OnDeprecated(DeprecatedError), ExcludeNames("Y", "Z")))
+			t.Run("Methods", func(t *testing.T) {
+				_, err := New(&Config{TargetPackage: TestTarget, Pattern: TestPattern},
+					OnDeprecated(DeprecatedError), ExcludeNames("Y", "Z"), ForwardMethods(true))
+				assert.ErrorIs(t, err, ErrDeprecated)
+				assert.ErrorContains(t, err, "P.Old")
+			})
+		})
+	})
+	t.Run("InPlaceWrites", AliaserTest(func(t *testing.T, a *Aliaser) {
+		name := filepath.Join(t.TempDir(), "alias.go")
+		require.NoError(t, os.WriteFile(name, []byte("package foo\n"), 0o644))
+		require.NoError(t, a.GenerateFile(name))
+		var buf bytes.Buffer
+		require.NoError(t, a.Generate(&buf))
+		data, err := os.ReadFile(name)
+		require.NoError(t, err)
+		assert.Equal(t, buf.String(), string(data))
+	}, InPlaceWrites(true)))
+	t.Run("WithImportStrategy", func(t *testing.T) {
+		t.Run("Default", AliaserTest(func(t *testing.T, a *Aliaser) {
+			var buf bytes.Buffer
+			require.NoError(t, a.Generate(&buf))
+			assert.Contains(t, buf.String(), "\tpkg \""+TestPattern+"\"\n")
+		}))
+		t.Run("AliasConflicts", AliaserTest(func(t *testing.T, a *Aliaser) {
+			var buf bytes.Buffer
+			require.NoError(t, a.Generate(&buf))
+			assert.Contains(t, buf.String(), "\t\""+TestPattern+"\"\n")
+			assert.NotContains(t, buf.String(), "pkg \""+TestPattern+"\"")
+		}, WithImportStrategy(importer.AliasConflicts{})))
+		t.Run("AliasPinned", AliaserTest(func(t *testing.T, a *Aliaser) {
+			var buf bytes.Buffer
+			require.NoError(t, a.Generate(&buf))
+			assert.Contains(t, buf.String(), "\tpinned \""+TestPattern+"\"\n")
+			assert.Contains(t, buf.String(), "= pinned.")
+		}, WithImportStrategy(importer.AliasPinned{TestPattern: "pinned"})))
+	})
+	t.Run("ReserveNames", AliaserTest(func(t *testing.T, a *Aliaser) {
+		assert.Equal(t, "pkg_2", a.AliasedImports()[TestPattern])
+		var buf bytes.Buffer
+		require.NoError(t, a.Generate(&buf))
+		assert.Contains(t, buf.String(), "\tpkg_2 \""+TestPattern+"\"\n")
+	}, ReserveNames("pkg")))
+	t.Run("WithImportAliases", AliaserTest(func(t *testing.T, a *Aliaser) {
+		assert.True(t, a.Frozen())
+		assert.Equal(t, "pkg_2", a.AliasedImports()[TestPattern])
+		var buf bytes.Buffer
+		require.NoError(t, a.Generate(&buf))
+		assert.Contains(t, buf.String(), "\tpkg_2 \""+TestPattern+"\"\n")
+	}, WithImportAliases(map[string]string{TestPattern: "pkg_2"})))
 	t.Run("OnDuplicate", func(t *testing.T) {
 		t.Run("Skip", AliaserTest(func(t *testing.T, a *Aliaser) {
 			v0 := a.variables[0]
@@ -120,6 +357,186 @@ func TestAliaserOptions(t *testing.T) {
 			v0 := a.variables[0]
 			assert.Panics(t, func() { a.AddVariables(types.NewVar(0, v0.Pkg(), "A", types.Typ[types.Uint8])) })
 		}, OnDuplicate(OnDuplicatePanic)))
+		t.Run("Prefix", AliaserTest(func(t *testing.T, a *Aliaser) {
+			pkg := a.variables[0].Pkg()
+			a.AddVariables(types.NewVar(0, pkg, "A", types.Typ[types.Uint8]))
+			a.AddFunctions(types.NewFunc(0, pkg, "A", types.NewSignatureType(nil, nil, nil, nil, nil, false)))
+			assert.True(t, slices.ContainsFunc(a.constants, func(c *Const) bool { return c.AliasName() == "A" }))
+			assert.True(t, slices.ContainsFunc(a.variables, func(v *Var) bool { return v.AliasName() == "PkgA" }))
+			assert.True(t, slices.ContainsFunc(a.functions, func(fn *Func) bool { return fn.AliasName() == "PkgA_" }))
+			var buf bytes.Buffer
+			require.NoError(t, a.Generate(&buf))
+			assert.Contains(t, buf.String(), "PkgA = pkg.A")
+			assert.Contains(t, buf.String(), "func PkgA_() {\n\tpkg.A()\n}")
+		}, OnDuplicate(OnDuplicatePrefix)))
+		t.Run("Rename", AliaserTest(func(t *testing.T, a *Aliaser) {
+			pkg := a.variables[0].Pkg()
+			require.NoError(t, a.AddVariables(types.NewVar(0, pkg, "A", types.Typ[types.Uint8])))
+			require.NoError(t, a.AddTypes(types.NewTypeName(0, pkg, "A", types.Typ[types.Uint8])))
+			require.NoError(t, a.AddFunctions(types.NewFunc(0, pkg, "B", types.NewSignatureType(nil, nil, nil, nil, nil, false))))
+			assert.True(t, slices.ContainsFunc(a.variables, func(v *Var) bool { return v.AliasName() == "AVar" }))
+			assert.True(t, slices.ContainsFunc(a.types, func(tn *TypeName) bool { return tn.AliasName() == "ATypeName" }))
+			// fall back to the package prefix if the name is not valid
+			assert.True(t, slices.ContainsFunc(a.functions, func(fn *Func) bool { return fn.AliasName() == "PkgB" }))
+		}, OnDuplicate(OnDuplicateRename), RenameDuplicates(func(obj types.Object, name string) string {
+			if _, ok := obj.(*types.Func); ok {
+				return "-"
+			}
+			return name + strings.TrimPrefix(fmt.Sprintf("%T", obj), "*types.")
+		})))
+		t.Run("RenameDefault", AliaserTest(func(t *testing.T, a *Aliaser) {
+			require.NoError(t, a.AddVariables(types.NewVar(0, a.variables[0].Pkg(), "A", types.Typ[types.Uint8])))
+			assert.True(t, slices.ContainsFunc(a.variables, func(v *Var) bool { return v.AliasName() == "PkgA" }))
+		}, OnDuplicate(OnDuplicateRename)))
+		t.Run("Error", AliaserTest(func(t *testing.T, a *Aliaser) {
+			pkg := a.variables[0].Pkg()
+			err := a.AddVariables(
+				types.NewVar(0, pkg, "A", types.Typ[types.Uint8]),
+				types.NewVar(0, pkg, "Fresh", types.Typ[types.Uint8]),
+				types.NewVar(0, pkg, "B", types.Typ[types.Uint8]),
+			)
+			var derr *DuplicateError
+			require.ErrorAs(t, err, &derr)
+			assert.ErrorIs(t, err, ErrDuplicate)
+			require.Len(t, derr.Duplicates, 2)
+			assert.Equal(t, "A", derr.Duplicates[0].Name)
+			assert.Equal(t, "B", derr.Duplicates[1].Name)
+			assert.Equal(t, "duplicate object names: A; B", err.Error())
+			assert.True(t, slices.ContainsFunc(a.variables, func(v *Var) bool { return v.Name() == "Fresh" }))
+			assert.False(t, slices.ContainsFunc(a.variables, func(v *Var) bool { return v.Name() == "A" }))
+			// the duplicates are returned only once
+			assert.NoError(t, a.AddConstants(types.NewConst(0, pkg, "Fresher", types.Typ[types.Uint8], nil)))
+		}, OnDuplicate(OnDuplicateError)))
+	})
+	t.Run("OnDuplicateSetter", func(t *testing.T) {
+		const pattern = "github.com/marcozac/go-aliaser/internal/testing/setter"
+		c := func() *Config { return &Config{TargetPackage: TestTarget, Pattern: pattern} }
+		accessors := WithVarStrategy(VarAccessors, "Foo")
+		generate := func(t *testing.T, a *Aliaser) string {
+			t.Helper()
+			var buf bytes.Buffer
+			require.NoError(t, a.Generate(&buf))
+			return buf.String()
+		}
+		t.Run("Skip", ConfigTest(func(t *testing.T, a *Aliaser) {
+			assert.Empty(t, a.Functions())
+			out := generate(t, a)
+			assert.Equal(t, 1, strings.Count(out, "func SetFoo("))
+			assert.Contains(t, out, "func SetFoo(v int) {\n\tsetter.Foo = v\n}")
+		}, c(), accessors))
+		t.Run("Replace", ConfigTest(func(t *testing.T, a *Aliaser) {
+			assert.Empty(t, a.Variables())
+			assert.False(t, a.names.Exist("Foo"))
+			out := generate(t, a)
+			assert.Equal(t, 1, strings.Count(out, "func SetFoo("))
+			assert.Contains(t, out, "func SetFoo(v int) {\n\tsetter.SetFoo(v)\n}")
+		}, c(), accessors, OnDuplicate(OnDuplicateReplace)))
+		t.Run("ReplaceTarget", AliaserTest(func(t *testing.T, a *Aliaser) {
+			// the setter name is declared in the target package
+			a.names.Put("SetA", targetId)
+			require.NoError(t, a.AddVariables(types.NewVar(0, a.variables[0].Pkg(), "A", types.Typ[types.Int])))
+			assert.True(t, slices.ContainsFunc(a.constants, func(c *Const) bool { return c.Name() == "A" }), "not evicted")
+			assert.False(t, slices.ContainsFunc(a.variables, func(v *Var) bool { return v.Name() == "A" }))
+			id, _ := a.names.Get("A")
+			assert.Equal(t, constantId, id)
+		}, WithVarStrategy(VarAccessors, "A"), OnDuplicate(OnDuplicateReplace)))
+		t.Run("Prefix", ConfigTest(func(t *testing.T, a *Aliaser) {
+			out := generate(t, a)
+			assert.Contains(t, out, "func SetFoo(v int) {\n\tsetter.Foo = v\n}")
+			assert.Contains(t, out, "func SetterSetFoo(v int) {\n\tsetter.SetFoo(v)\n}")
+		}, c(), accessors, OnDuplicate(OnDuplicatePrefix)))
+		t.Run("Rename", ConfigTest(func(t *testing.T, a *Aliaser) {
+			assert.Contains(t, generate(t, a), "func SetFooFunc(v int) {")
+		}, c(), accessors, OnDuplicate(OnDuplicateRename), RenameDuplicates(func(_ types.Object, name string) string {
+			return name + "Func"
+		})))
+		t.Run("Error", func(t *testing.T) {
+			_, err := New(c(), accessors, OnDuplicate(OnDuplicateError))
+			var derr *DuplicateError
+			require.ErrorAs(t, err, &derr)
+			require.Len(t, derr.Duplicates, 1)
+			assert.Equal(t, "SetFoo", derr.Duplicates[0].Name)
+		})
+		t.Run("SetterSkipped", ConfigTest(func(t *testing.T, a *Aliaser) {
+			// the setter of a variable added later cannot be declared
+			require.NoError(t, a.AddFunctions(types.NewFunc(0, a.Functions()[0].Pkg(), "SetBar", types.NewSignatureType(nil, nil, nil, nil, nil, false))))
+			require.NoError(t, a.AddVariables(types.NewVar(0, a.Functions()[0].Pkg(), "Bar", types.Typ[types.Int])))
+			assert.Empty(t, a.Variables())
+			assert.False(t, a.names.Exist("Bar"))
+		}, c(), ExcludeVariables(true), WithVarStrategy(VarAccessors)))
+	})
+	t.Run("MergePackages", func(t *testing.T) {
+		const pattern = "github.com/marcozac/go-aliaser/internal/testing/merge/..."
+		t.Run("Disabled", func(t *testing.T) {
+			_, err := New(&Config{TargetPackage: TestTarget, Pattern: pattern})
+			assert.ErrorContains(t, err, "expected one package, got 2")
+		})
+		t.Run("Skip", ConfigTest(func(t *testing.T, a *Aliaser) {
+			var buf bytes.Buffer
+			require.NoError(t, a.Generate(&buf))
+			assert.Contains(t, buf.String(), "Error = api.Error")
+			assert.NotContains(t, buf.String(), "= errors.Error")
+			assert.Contains(t, buf.String(), "func Wrap(err *api.Error) *errors.Error {")
+		}, &Config{TargetPackage: TestTarget, Pattern: pattern}, MergePackages(true)))
+		t.Run("Prefix", ConfigTest(func(t *testing.T, a *Aliaser) {
+			var buf bytes.Buffer
+			require.NoError(t, a.Generate(&buf))
+			assert.Contains(t, buf.String(), "Error = api.Error")
+			assert.Contains(t, buf.String(), "ErrorsError = errors.Error")
+			assert.Contains(t, buf.String(), "// Wrap returns a new [ErrorsError] wrapping the given API error.")
+			assert.Contains(t, buf.String(), "// ErrorsError is an error wrapping an [api.Error].")
+		}, &Config{TargetPackage: TestTarget, Pattern: pattern}, MergePackages(true), OnDuplicate(OnDuplicatePrefix), RewriteDocLinks(true)))
+		t.Run("Error", func(t *testing.T) {
+			_, err := New(&Config{TargetPackage: TestTarget, Pattern: pattern}, MergePackages(true), OnDuplicate(OnDuplicateError))
+			var derr *DuplicateError
+			require.ErrorAs(t, err, &derr)
+			require.Len(t, derr.Duplicates, 1)
+			assert.Equal(t, "Error", derr.Duplicates[0].Name)
+			assert.Equal(t, "errors", derr.Duplicates[0].Object.Pkg().Name())
+		})
+		t.Run("Patterns", ConfigTest(func(t *testing.T, a *Aliaser) {
+			assert.Len(t, a.Types(), 2)
+			assert.Len(t, a.Functions(), 2)
+			assert.Len(t, a.Constants(), 1)
+		}, &Config{
+			TargetPackage: TestTarget,
+			Patterns: []string{
+				"github.com/marcozac/go-aliaser/internal/testing/merge/api",
+				"github.com/marcozac/go-aliaser/internal/testing/merge/errors",
+			},
+		}, OnDuplicate(OnDuplicatePrefix)))
+	})
+	t.Run("Loader", func(t *testing.T) {
+		hasFunc := func(name string) func(*Func) bool {
+			return func(fn *Func) bool { return fn.Name() == name }
+		}
+		t.Run("WithDir", ConfigTest(func(t *testing.T, a *Aliaser) {
+			assert.NotEmpty(t, a.Constants())
+			assert.NotEmpty(t, a.GoVersion)
+		}, &Config{TargetPackage: TestTarget, Pattern: "."}, WithDir("internal/testing/pkg")))
+		t.Run("WithEnv", ConfigTest(func(t *testing.T, a *Aliaser) {
+			assert.True(t, slices.ContainsFunc(a.Functions(), hasFunc("Foo")))
+		}, &Config{TargetPackage: TestTarget, Pattern: TestPlatformPattern}, WithEnv("GOFLAGS=-tags=foo")))
+		t.Run("WithBuildFlags", ConfigTest(func(t *testing.T, a *Aliaser) {
+			assert.True(t, slices.ContainsFunc(a.Functions(), hasFunc("Foo")))
+		}, &Config{TargetPackage: TestTarget, Pattern: TestPlatformPattern}, WithBuildFlags("-tags=foo")))
+		overlay, err := filepath.Abs("internal/testing/pkg/overlay.go")
+		require.NoError(t, err)
+		t.Run("WithOverlay", ConfigTest(func(t *testing.T, a *Aliaser) {
+			assert.True(t, slices.ContainsFunc(a.Constants(), func(c *Const) bool { return c.Name() == "Overlay" }))
+		}, &Config{TargetPackage: TestTarget, Pattern: TestPattern}, WithOverlay(map[string][]byte{
+			overlay: []byte("package pkg\n\nconst Overlay = 1\n"),
+		})))
+		t.Run("Platform", func(t *testing.T) {
+			c := new(Config).setDefaults().applyOptions(
+				WithEnv("GOFLAGS=-mod=mod"),
+				WithBuildFlags("-tags=bar"),
+			)
+			c.platform = &Platform{GOOS: "darwin", Tags: []string{"foo"}}
+			pc := c.packagesConfig(loadMode)
+			assert.Equal(t, []string{"GOFLAGS=-mod=mod", "GOOS=darwin"}, pc.Env[len(pc.Env)-2:])
+			assert.Equal(t, []string{"-tags=bar", "-tags=foo"}, pc.BuildFlags)
+		})
 	})
 }
 
@@ -137,6 +554,10 @@ func TestAliaserError(t *testing.T) {
 		_, err := New(&Config{TargetPackage: TestTarget, Pattern: "golang.org/x/tools/go/..."})
 		assert.Error(t, err)
 	})
+	t.Run("NoPackages", func(t *testing.T) {
+		_, err := New(&Config{TargetPackage: TestTarget, Pattern: TestPattern + "/none/..."})
+		assert.ErrorIs(t, err, ErrNoPackages)
+	})
 	t.Run("Load", func(t *testing.T) {
 		t.Setenv("GOPACKAGESDRIVER", "fakedriver")
 		_, err := New(&Config{TargetPackage: TestTarget, Pattern: TestPattern})
@@ -183,11 +604,10 @@ func TestAliaserError(t *testing.T) {
 	t.Run("addObjectName", AliaserTest(func(t *testing.T, a *Aliaser) {
 		a.onDuplicate = 10
 		v := types.NewVar(0, a.variables[0].Pkg(), "A", types.Typ[types.Uint8])
-		assert.Panics(t, func() { a.addObjectName(v, 10) })
+		assert.Panics(t, func() { a.addObjectName(v, v.Name(), 10) })
 	}))
 	t.Run("deleteObject", AliaserTest(func(t *testing.T, a *Aliaser) {
-		v := types.NewVar(0, a.variables[0].Pkg(), "A", types.Typ[types.Uint8])
-		assert.Panics(t, func() { a.deleteObject(v, 10) })
+		assert.Panics(t, func() { a.deleteObject("A", 10) })
 	}))
 	t.Run("Generate", AliaserTest(func(t *testing.T, a *Aliaser) {
 		opener := openFile
@@ -200,6 +620,19 @@ func TestAliaserError(t *testing.T) {
 		c0 := a.Constants()[0] // change the first constant to have an invalid name
 		a.constants[0] = NewConst(types.NewConst(c0.Pos(), c0.Pkg(), c0.Name()+".", c0.Type(), c0.Val()), a.Importer)
 		assert.Error(t, a.GenerateFile(tf.Name()))
+		data, err := os.ReadFile(tf.Name())
+		require.NoError(t, err)
+		assert.Equal(t, "package foo\n", string(data))
+		entries, err := os.ReadDir(dir)
+		require.NoError(t, err)
+		assert.Len(t, entries, 1, "temporary file removed")
+		t.Run("InPlace", func(t *testing.T) {
+			a.inPlaceWrites = true
+			assert.Error(t, a.GenerateFile(tf.Name()))
+			data, err := os.ReadFile(tf.Name())
+			require.NoError(t, err)
+			assert.Equal(t, "package foo\n", string(data))
+		})
 		t.Run("Reset", func(t *testing.T) {
 			defer func() { openFile = opener }()
 			openFile = newOpenFileErrorer(fileErrorerConfig{
@@ -207,6 +640,7 @@ func TestAliaserError(t *testing.T) {
 				noSeekErr:  true,
 				noTruncErr: true,
 			})
+			a.constants[0] = c0 // fail on write
 			assert.Error(t, a.GenerateFile(tf.Name()))
 		})
 	}))
@@ -216,9 +650,15 @@ func TestAliaserError(t *testing.T) {
 // method, that creates a new valid [Aliaser] with [TestTarget], [TestPattern]
 // and the options, then, it calls the given function.
 func AliaserTest(fn func(*testing.T, *Aliaser), opts ...Option) func(t *testing.T) {
+	return ConfigTest(fn, &Config{TargetPackage: TestTarget, Pattern: TestPattern}, opts...)
+}
+
+// ConfigTest is like [AliaserTest], but creates the [Aliaser] with the given
+// configuration.
+func ConfigTest(fn func(*testing.T, *Aliaser), c *Config, opts ...Option) func(t *testing.T) {
 	return func(t *testing.T) {
-		a, err := New(&Config{TargetPackage: TestTarget, Pattern: TestPattern}, opts...)
-		assert.NoError(t, err)
+		a, err := New(c, opts...)
+		require.NoError(t, err)
 		require.NotNil(t, a)
 		fn(t, a)
 	}
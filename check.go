@@ -0,0 +1,98 @@
+package aliaser
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// Check loads the package defined by the given pattern, generates the aliases
+// for the target package name, and compares the result with the file with the
+// given name, without writing anything.
+//
+// Under the hood, Check creates a new [Aliaser] with the given parameters and
+// calls [Aliaser.Check] with the file name.
+func Check(target, pattern, name string, opts ...Option) error {
+	a, err := New(&Config{TargetPackage: target, Pattern: pattern}, opts...)
+	if err != nil {
+		return fmt.Errorf("aliaser: %w", err)
+	}
+	return a.Check(name)
+}
+
+// Check generates the aliases in memory, as [Aliaser.GenerateFile] would
+// write them to the file with the given name, and compares the result with
+// the files on disk, without modifying them. It is intended to detect, for
+// example in CI, the generated files that are out of date after a change of
+// the aliased packages or of the options.
+//
+// If target platforms are set (see [WithPlatforms]), the platform specific
+// files named by [PlatformFileName] are checked as well.
+//
+// Check returns a [*StaleError] listing the files that differ from the
+// generated ones, or do not exist, with the unified diff of each of them. It
+// returns a different error if it fails to generate the code or to read the
+// files.
+//
+// Example:
+//
+//	err := a.Check("mypkg/alias.go")
+//	if serr := (*aliaser.StaleError)(nil); errors.As(err, &serr) {
+//		fmt.Print(serr.Diff())
+//	}
+func (a *Aliaser) Check(name string) error {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	var stale []StaleFile
+	sf, err := a.checkFile(name)
+	if err != nil {
+		return err
+	}
+	if sf != nil {
+		stale = append(stale, *sf)
+	}
+	for _, pf := range a.platformFiles {
+		sf, err := pf.view.checkFile(PlatformFileName(name, pf.platforms...))
+		if err != nil {
+			return err
+		}
+		if sf != nil {
+			stale = append(stale, *sf)
+		}
+	}
+	if len(stale) > 0 {
+		return &StaleError{Files: stale}
+	}
+	return nil
+}
+
+// checkFile compares the generated code with the file with the given name,
+// returning the stale file if they differ or nil if they are equal.
+func (a *Aliaser) checkFile(name string) (*StaleFile, error) {
+	var buf bytes.Buffer
+	if err := a.generate(&buf); err != nil {
+		return nil, fmt.Errorf("generate: %w", err)
+	}
+	data, err := os.ReadFile(name)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("read file: %w", err)
+	}
+	if bytes.Equal(data, buf.Bytes()) {
+		return nil, nil
+	}
+	diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(string(data)),
+		B:        difflib.SplitLines(buf.String()),
+		FromFile: name,
+		ToFile:   name + " (generated)",
+		Context:  3,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("diff: %w", err)
+	}
+	return &StaleFile{Name: name, Diff: diff}, nil
+}
@@ -0,0 +1,58 @@
+package aliaser
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheck(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "alias.go")
+	err := Check(TestTarget, TestPattern, name)
+	serr := (*StaleError)(nil)
+	require.True(t, errors.As(err, &serr))
+	require.Len(t, serr.Files, 1)
+	assert.Equal(t, name, serr.Files[0].Name)
+	assert.Contains(t, serr.Diff(), "+package "+TestTarget)
+	assert.NoFileExists(t, name)
+
+	require.NoError(t, GenerateFile(TestTarget, TestPattern, name))
+	assert.NoError(t, Check(TestTarget, TestPattern, name))
+	assert.ErrorIs(t, Check(TestTarget, TestPattern, name, ExcludeConstants(true)), ErrStale)
+	t.Run("EmptyTarget", func(t *testing.T) {
+		assert.ErrorIs(t, Check("", TestPattern, name), ErrEmptyTarget)
+	})
+}
+
+func TestAliaserCheck(t *testing.T) {
+	t.Run("Platforms", func(t *testing.T) {
+		a, err := New(&Config{TargetPackage: "foo", Pattern: TestPlatformPattern}, WithPlatforms(
+			Platform{GOOS: "linux"},
+			Platform{GOOS: "darwin"},
+		))
+		require.NoError(t, err)
+		dir := t.TempDir()
+		name := filepath.Join(dir, "alias.go")
+		require.NoError(t, a.GenerateFile(name))
+		assert.NoError(t, a.Check(name))
+		linux := filepath.Join(dir, "alias_linux.go")
+		require.NoError(t, os.WriteFile(linux, []byte("package foo\n"), 0o644))
+		err = a.Check(name)
+		serr := (*StaleError)(nil)
+		require.True(t, errors.As(err, &serr))
+		require.Len(t, serr.Files, 1)
+		assert.Equal(t, linux, serr.Files[0].Name)
+		assert.Contains(t, serr.Files[0].Diff, "+func Linux() {\n")
+	})
+	t.Run("ReadError", AliaserTest(func(t *testing.T, a *Aliaser) {
+		assert.Error(t, a.Check(t.TempDir())) // directory
+	}))
+	t.Run("GenerateError", func(t *testing.T) {
+		a := &Aliaser{}
+		assert.Error(t, a.Check(filepath.Join(t.TempDir(), "alias.go")))
+	})
+}
@@ -0,0 +1,32 @@
+package internal
+
+import (
+	"errors"
+
+	"github.com/marcozac/go-aliaser"
+	"github.com/spf13/cobra"
+)
+
+func NewCheck() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "check",
+		Short: "check that the generated file is up to date, printing the diff if not",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			a, err := newAliaser(cmd)
+			if err != nil {
+				return err
+			}
+			err = a.Check(MustV(cmd.Flags().GetString("file")))
+			if serr := (*aliaser.StaleError)(nil); errors.As(err, &serr) {
+				cmd.SilenceUsage = true
+				cmd.Print(serr.Diff())
+			}
+			return err
+		},
+	}
+	cmd.Flags().String("file", "", "the file name of the generated aliases to check")
+	addAliaserFlags(cmd)
+
+	Must(cmd.MarkFlagRequired("file"))
+	return cmd
+}
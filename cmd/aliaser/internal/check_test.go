@@ -0,0 +1,62 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckCmd(t *testing.T) {
+	t.Run("RequiredFlags", func(t *testing.T) {
+		root, buf := NewTestRoot(t)
+		root.SetArgs([]string{"check"})
+		assert.Error(t, root.Execute())
+		assert.Contains(t, buf.String(), "required flag(s) \"file\", \"pattern\", \"target\" not set")
+	})
+	t.Run("Check", func(t *testing.T) {
+		filename := filepath.Join(t.TempDir(), "alias.go")
+		args := []string{
+			"--target", "foo",
+			"--pattern", TestPattern,
+			"--file", filename,
+		}
+		root, buf := NewTestRoot(t)
+		root.SetArgs(append([]string{"check"}, args...))
+		assert.Error(t, root.Execute(), "missing file")
+		assert.Contains(t, buf.String(), "+package foo")
+		assert.NoFileExists(t, filename)
+
+		root, _ = NewTestRoot(t)
+		root.SetArgs(append([]string{"generate"}, args...))
+		require.NoError(t, root.Execute())
+		root, buf = NewTestRoot(t)
+		root.SetArgs(append([]string{"check"}, args...))
+		assert.NoError(t, root.Execute())
+		assert.Empty(t, buf.String())
+
+		require.NoError(t, os.WriteFile(filename, []byte("package foo\n"), 0o644))
+		root, buf = NewTestRoot(t)
+		root.SetArgs(append([]string{"check"}, args...))
+		assert.Error(t, root.Execute())
+		assert.Contains(t, buf.String(), "--- "+filename+"\n")
+		assert.Contains(t, buf.String(), "+++ "+filename+" (generated)\n")
+		assert.NotContains(t, buf.String(), "Usage:")
+		data, err := os.ReadFile(filename)
+		require.NoError(t, err)
+		assert.Equal(t, "package foo\n", string(data), "file not modified")
+	})
+	t.Run("OptionsError", func(t *testing.T) {
+		root, _ := NewTestRoot(t)
+		root.SetArgs([]string{
+			"check",
+			"--target", "foo",
+			"--pattern", TestPattern,
+			"--file", filepath.Join(t.TempDir(), "alias.go"),
+			"--var-strategy", "invalid",
+		})
+		assert.Error(t, root.Execute())
+	})
+}
@@ -11,45 +11,60 @@ func NewGenerate() *cobra.Command {
 	cmd := &cobra.Command{
 		Use: "generate",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			opts := []aliaser.Option{
-				aliaser.WithContext(cmd.Context()),
-				aliaser.ExcludeConstants(MustV(cmd.Flags().GetBool("exclude-constants"))),
-				aliaser.ExcludeVariables(MustV(cmd.Flags().GetBool("exclude-variables"))),
-				aliaser.ExcludeFunctions(MustV(cmd.Flags().GetBool("exclude-functions"))),
-				aliaser.ExcludeTypes(MustV(cmd.Flags().GetBool("exclude-types"))),
-				aliaser.ExcludeNames(MustV(cmd.Flags().GetStringSlice("exclude-names"))...),
-				aliaser.AssignFunctions(MustV(cmd.Flags().GetBool("assign-functions"))),
-			}
-			if header := MustV(cmd.Flags().GetString("header")); header != "" {
-				opts = append(opts, aliaser.WithHeader(header))
-			}
-			a, err := aliaser.New(&aliaser.Config{
-				TargetPackage: MustV(cmd.Flags().GetString("target")),
-				Pattern:       MustV(cmd.Flags().GetString("pattern")),
-			}, opts...)
+			a, err := newAliaser(cmd)
 			if err != nil {
-				return fmt.Errorf("aliaser: %w", err)
+				return err
 			}
 			if MustV(cmd.Flags().GetBool("dry-run")) {
 				return a.Generate(cmd.OutOrStdout())
 			}
-			return a.GenerateFile(MustV(cmd.Flags().GetString("file")))
+			files, err := a.GenerateFiles(MustV(cmd.Flags().GetString("file")))
+			for _, f := range files {
+				if f.Changed {
+					cmd.Println("updated:", f.Name)
+				} else {
+					cmd.Println("unchanged:", f.Name)
+				}
+			}
+			return err
 		},
 	}
-	cmd.Flags().String("target", "", "the package name to use in the generated file")
-	cmd.Flags().String("pattern", "", "the package pattern, in go format, to generate aliases for")
 	cmd.Flags().String("file", "", "the file name to write the aliases to")
-	cmd.Flags().String("header", "", "optional header to be written at the top of the file")
-	cmd.Flags().Bool("exclude-constants", false, "exclude constants from the generated aliases")
-	cmd.Flags().Bool("exclude-variables", false, "exclude variables from the generated aliases")
-	cmd.Flags().Bool("exclude-functions", false, "exclude functions from the generated aliases")
-	cmd.Flags().Bool("exclude-types", false, "exclude types from the generated aliases")
-	cmd.Flags().StringSlice("exclude-names", nil, "exclude specific names from the generated aliases")
-	cmd.Flags().Bool("assign-functions", false, "assign functions to variables in the generated aliases")
+	addAliaserFlags(cmd)
 	cmd.Flags().Bool("dry-run", false, "print the aliases without writing them to the file")
 
-	Must(cmd.MarkFlagRequired("target"))
-	Must(cmd.MarkFlagRequired("pattern"))
 	cmd.MarkFlagsOneRequired("file", "dry-run")
 	return cmd
 }
+
+// addAliaserFlags adds to the given command the flags used by [newAliaser],
+// except the file one, and marks the required ones.
+func addAliaserFlags(cmd *cobra.Command) {
+	cmd.Flags().String("target", "", "the package name to use in the generated file")
+	cmd.Flags().StringSlice("pattern", nil, "the package patterns, in go format, to generate aliases for (can be repeated to merge several packages)")
+	addOptionFlags(cmd)
+	cmd.Flags().Bool("merge-packages", false, "merge all the packages matched by the pattern into the target package")
+	cmd.Flags().Bool("stable-imports", false, "keep the import aliases of the existing file, if any, so that new imports do not rename them")
+
+	Must(cmd.MarkFlagRequired("target"))
+	Must(cmd.MarkFlagRequired("pattern"))
+}
+
+// newAliaser returns a new [aliaser.Aliaser] configured by the flags added by
+// [addAliaserFlags] to the given command.
+func newAliaser(cmd *cobra.Command) (*aliaser.Aliaser, error) {
+	jc, err := jobConfigFromFlags(cmd)
+	if err != nil {
+		return nil, err
+	}
+	jc.Target = MustV(cmd.Flags().GetString("target"))
+	jc.Patterns = MustV(cmd.Flags().GetStringSlice("pattern"))
+	jc.File = MustV(cmd.Flags().GetString("file"))
+	jc.MergePackages = MustV(cmd.Flags().GetBool("merge-packages"))
+	jc.StableImports = MustV(cmd.Flags().GetBool("stable-imports"))
+	a, err := jc.New(aliaser.WithContext(cmd.Context()))
+	if err != nil {
+		return nil, fmt.Errorf("aliaser: %w", err)
+	}
+	return a, nil
+}
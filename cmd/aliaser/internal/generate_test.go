@@ -2,11 +2,14 @@
 
 import (
 	"bytes"
+	"encoding/json"
+	"os"
 	"path/filepath"
 	"testing"
 
 	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // TestPattern is a valid pattern for testing.
@@ -57,6 +60,305 @@ func TestGenerateCmd(t *testing.T) {
 		assert.NotContains(t, buf.String(), "A = pkg.A")
 		assert.NotContains(t, buf.String(), "C = pkg.C")
 	})
+	t.Run("NameFilters", func(t *testing.T) {
+		root, buf := NewTestRoot(t)
+		root.SetArgs([]string{
+			"generate", "--dry-run",
+			"--target", "foo",
+			"--pattern", TestPattern,
+			"--include", "func:[ST]",
+			"--include", "func:/^U$/",
+			"--exclude", "S",
+			"--exclude", "type:*",
+		})
+		assert.NoError(t, root.Execute())
+		assert.Contains(t, buf.String(), "func T[")
+		assert.Contains(t, buf.String(), "func U[")
+		assert.NotContains(t, buf.String(), "func S[")
+		assert.NotContains(t, buf.String(), "func C(")
+		assert.NotContains(t, buf.String(), "type ")
+		assert.Contains(t, buf.String(), "A = pkg.A")
+		t.Run("Invalid", func(t *testing.T) {
+			for _, filter := range []string{"func:", "["} {
+				root, _ := NewTestRoot(t)
+				root.SetArgs([]string{
+					"generate", "--dry-run",
+					"--target", "foo",
+					"--pattern", TestPattern,
+					"--include", filter,
+				})
+				assert.Error(t, root.Execute(), filter)
+			}
+		})
+	})
+	t.Run("Rename", func(t *testing.T) {
+		root, buf := NewTestRoot(t)
+		root.SetArgs([]string{
+			"generate", "--dry-run",
+			"--target", "foo",
+			"--pattern", "github.com/marcozac/go-aliaser/internal/testing/rename",
+			"--rename-prefix", "X",
+			"--rename-suffix", "V2",
+			"--rename-regexp", "^New(.+)$=Make$1",
+			"--rename-regexp", "Client=HTTPClient",
+			"--rename", "Version=Ver",
+		})
+		assert.NoError(t, root.Execute())
+		assert.Contains(t, buf.String(), "Ver = rename.Version")
+		assert.Contains(t, buf.String(), "func XMakeHTTPClientV2() *XHTTPClientV2 {")
+		t.Run("Invalid", func(t *testing.T) {
+			for _, rule := range []string{"Client", "(=Foo"} {
+				root, _ := NewTestRoot(t)
+				root.SetArgs([]string{
+					"generate", "--dry-run",
+					"--target", "foo",
+					"--pattern", TestPattern,
+					"--rename-regexp", rule,
+				})
+				assert.Error(t, root.Execute(), rule)
+			}
+		})
+	})
+	t.Run("ForwardMethods", func(t *testing.T) {
+		root, buf := NewTestRoot(t)
+		root.SetArgs([]string{
+			"generate", "--dry-run",
+			"--target", "foo",
+			"--pattern", TestPattern,
+			"--forward-methods",
+		})
+		assert.NoError(t, root.Execute())
+		assert.Contains(t, buf.String(), "func (r *P[T, V]) Foo() {")
+	})
+	t.Run("VarStrategy", func(t *testing.T) {
+		root, buf := NewTestRoot(t)
+		root.SetArgs([]string{
+			"generate", "--dry-run",
+			"--target", "foo",
+			"--pattern", TestPattern,
+			"--var-strategy", "pointer",
+			"--var-strategies", "X=accessors",
+		})
+		assert.NoError(t, root.Execute())
+		assert.Contains(t, buf.String(), "B = &pkg.B")
+		assert.Contains(t, buf.String(), "func SetX(v []string) {")
+		t.Run("Invalid", func(t *testing.T) {
+			root, _ := NewTestRoot(t)
+			root.SetArgs([]string{
+				"generate", "--dry-run",
+				"--target", "foo",
+				"--pattern", TestPattern,
+				"--var-strategy", "invalid",
+			})
+			assert.Error(t, root.Execute())
+			root.SetArgs([]string{
+				"generate", "--dry-run",
+				"--target", "foo",
+				"--pattern", TestPattern,
+				"--var-strategies", "X=invalid",
+			})
+			assert.Error(t, root.Execute())
+		})
+	})
+	t.Run("RewriteDocLinks", func(t *testing.T) {
+		root, buf := NewTestRoot(t)
+		root.SetArgs([]string{
+			"generate", "--dry-run",
+			"--target", "foo",
+			"--pattern", TestPattern,
+			"--rewrite-doc-links",
+		})
+		assert.NoError(t, root.Execute())
+		assert.Contains(t, buf.String(), "[json_2.Foo]")
+	})
+	t.Run("OnDeprecated", func(t *testing.T) {
+		root, buf := NewTestRoot(t)
+		root.SetArgs([]string{
+			"generate", "--dry-run",
+			"--target", "foo",
+			"--pattern", TestPattern,
+			"--on-deprecated", "exclude",
+		})
+		assert.NoError(t, root.Execute())
+		assert.NotContains(t, buf.String(), "func Z() {")
+		t.Run("Invalid", func(t *testing.T) {
+			root, _ := NewTestRoot(t)
+			root.SetArgs([]string{
+				"generate", "--dry-run",
+				"--target", "foo",
+				"--pattern", TestPattern,
+				"--on-deprecated", "invalid",
+			})
+			assert.Error(t, root.Execute())
+		})
+	})
+	t.Run("MergePackages", func(t *testing.T) {
+		root, buf := NewTestRoot(t)
+		root.SetArgs([]string{
+			"generate", "--dry-run",
+			"--target", "foo",
+			"--pattern", "github.com/marcozac/go-aliaser/internal/testing/merge/...",
+			"--merge-packages",
+		})
+		assert.NoError(t, root.Execute())
+		assert.Contains(t, buf.String(), "Error = api.Error")
+		t.Run("Patterns", func(t *testing.T) {
+			root, buf := NewTestRoot(t)
+			root.SetArgs([]string{
+				"generate", "--dry-run",
+				"--target", "foo",
+				"--pattern", "github.com/marcozac/go-aliaser/internal/testing/merge/api",
+				"--pattern", "github.com/marcozac/go-aliaser/internal/testing/merge/errors",
+			})
+			assert.NoError(t, root.Execute())
+			assert.Contains(t, buf.String(), "func Wrap(")
+		})
+		t.Run("ImportAliases", func(t *testing.T) {
+			root, buf := NewTestRoot(t)
+			root.SetArgs([]string{
+				"generate", "--dry-run",
+				"--target", "foo",
+				"--pattern", "github.com/marcozac/go-aliaser/internal/testing/merge/...",
+				"--merge-packages",
+				"--import-alias", "github.com/marcozac/go-aliaser/internal/testing/merge/errors=merrors",
+			})
+			assert.NoError(t, root.Execute())
+			assert.Contains(t, buf.String(), `merrors "github.com/marcozac/go-aliaser/internal/testing/merge/errors"`)
+			root, buf = NewTestRoot(t)
+			root.SetArgs([]string{
+				"generate", "--dry-run",
+				"--target", "foo",
+				"--pattern", "github.com/marcozac/go-aliaser/internal/testing/merge/...",
+				"--merge-packages",
+				"--import-aliases", "conflicts",
+				"--reserve-names", "errors",
+			})
+			assert.NoError(t, root.Execute())
+			assert.Contains(t, buf.String(), `errors_2 "github.com/marcozac/go-aliaser/internal/testing/merge/errors"`)
+			root, _ = NewTestRoot(t)
+			root.SetArgs([]string{
+				"generate", "--dry-run",
+				"--target", "foo",
+				"--pattern", "github.com/marcozac/go-aliaser/internal/testing/merge/...",
+				"--merge-packages",
+				"--import-aliases", "invalid",
+			})
+			assert.Error(t, root.Execute())
+		})
+		t.Run("OnDuplicate", func(t *testing.T) {
+			root, buf := NewTestRoot(t)
+			root.SetArgs([]string{
+				"generate", "--dry-run",
+				"--target", "foo",
+				"--pattern", "github.com/marcozac/go-aliaser/internal/testing/merge/...",
+				"--merge-packages",
+				"--on-duplicate", "rename",
+			})
+			assert.NoError(t, root.Execute())
+			assert.Contains(t, buf.String(), "ErrorsError = errors.Error")
+			for _, policy := range []string{"error", "invalid"} {
+				root, _ := NewTestRoot(t)
+				root.SetArgs([]string{
+					"generate", "--dry-run",
+					"--target", "foo",
+					"--pattern", "github.com/marcozac/go-aliaser/internal/testing/merge/...",
+					"--merge-packages",
+					"--on-duplicate", policy,
+				})
+				assert.Error(t, root.Execute(), policy)
+			}
+		})
+	})
+	t.Run("Platform", func(t *testing.T) {
+		tempDir := t.TempDir()
+		root, _ := NewTestRoot(t)
+		root.SetArgs([]string{
+			"generate",
+			"--target", "foo",
+			"--pattern", "github.com/marcozac/go-aliaser/internal/testing/platform",
+			"--file", filepath.Join(tempDir, "alias.go"),
+			"--platform", "linux",
+			"--platform", "darwin:foo",
+		})
+		assert.NoError(t, root.Execute())
+		assert.FileExists(t, filepath.Join(tempDir, "alias.go"))
+		assert.FileExists(t, filepath.Join(tempDir, "alias_linux.go"))
+		assert.FileExists(t, filepath.Join(tempDir, "alias_darwin_foo_build.go"))
+		t.Run("Invalid", func(t *testing.T) {
+			root, _ := NewTestRoot(t)
+			root.SetArgs([]string{
+				"generate", "--dry-run",
+				"--target", "foo",
+				"--pattern", TestPattern,
+				"--platform", "linux/amd 64",
+			})
+			assert.Error(t, root.Execute())
+		})
+	})
+	t.Run("Loader", func(t *testing.T) {
+		root, buf := NewTestRoot(t)
+		root.SetArgs([]string{
+			"generate", "--dry-run",
+			"--target", "foo",
+			"--pattern", ".",
+			"--dir", "../../../internal/testing/platform",
+			"--env", "GOFLAGS=-mod=mod",
+			"--build-flags", "-tags=foo",
+		})
+		assert.NoError(t, root.Execute())
+		assert.Contains(t, buf.String(), "func Foo() {")
+		t.Run("Overlay", func(t *testing.T) {
+			tempDir := t.TempDir()
+			replacement := filepath.Join(tempDir, "overlay.go")
+			require.NoError(t, os.WriteFile(replacement, []byte("package pkg\n\nconst Overlay = 1\n"), 0o644))
+			data, err := json.Marshal(map[string]any{
+				"Replace": map[string]string{"../../../internal/testing/pkg/overlay.go": replacement},
+			})
+			require.NoError(t, err)
+			overlay := filepath.Join(tempDir, "overlay.json")
+			require.NoError(t, os.WriteFile(overlay, data, 0o644))
+			root, buf := NewTestRoot(t)
+			root.SetArgs([]string{
+				"generate", "--dry-run",
+				"--target", "foo",
+				"--pattern", TestPattern,
+				"--overlay", overlay,
+			})
+			assert.NoError(t, root.Execute())
+			assert.Contains(t, buf.String(), "Overlay = pkg.Overlay")
+		})
+		t.Run("OverlayError", func(t *testing.T) {
+			tempDir := t.TempDir()
+			invalid := filepath.Join(tempDir, "invalid.json")
+			require.NoError(t, os.WriteFile(invalid, []byte("{"), 0o644))
+			missing := filepath.Join(tempDir, "missing.json")
+			require.NoError(t, os.WriteFile(missing, []byte(`{"Replace": {"a.go": "missing.go"}}`), 0o644))
+			for _, name := range []string{filepath.Join(tempDir, "none.json"), invalid, missing} {
+				root, _ := NewTestRoot(t)
+				root.SetArgs([]string{
+					"generate", "--dry-run",
+					"--target", "foo",
+					"--pattern", TestPattern,
+					"--overlay", name,
+				})
+				assert.Error(t, root.Execute(), name)
+			}
+		})
+	})
+	t.Run("TargetDir", func(t *testing.T) {
+		tempDir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(tempDir, "client.go"), []byte("package foo\n\ntype Client struct{}\n"), 0o644))
+		root, buf := NewTestRoot(t)
+		root.SetArgs([]string{
+			"generate", "--dry-run",
+			"--target", "foo",
+			"--pattern", "github.com/marcozac/go-aliaser/internal/testing/rename",
+			"--target-dir", tempDir,
+		})
+		assert.NoError(t, root.Execute())
+		assert.NotContains(t, buf.String(), "Client = rename.Client")
+		assert.Contains(t, buf.String(), "Version = rename.Version")
+	})
 	t.Run("Header", func(t *testing.T) {
 		root, buf := NewTestRoot(t)
 		root.SetArgs([]string{
@@ -71,7 +373,7 @@ func TestGenerateCmd(t *testing.T) {
 	t.Run("GenerateFile", func(t *testing.T) {
 		tempDir := t.TempDir()
 		filename := filepath.Join(tempDir, "alias.go")
-		root, _ := NewTestRoot(t)
+		root, buf := NewTestRoot(t)
 		root.SetArgs([]string{
 			"generate",
 			"--target", "foo",
@@ -80,6 +382,47 @@ func TestGenerateCmd(t *testing.T) {
 		})
 		assert.NoError(t, root.Execute())
 		assert.FileExists(t, filename)
+		assert.Equal(t, "updated: "+filename+"\n", buf.String())
+		root, buf = NewTestRoot(t)
+		root.SetArgs([]string{
+			"generate",
+			"--target", "foo",
+			"--pattern", TestPattern,
+			"--file", filename,
+			"--in-place",
+		})
+		assert.NoError(t, root.Execute())
+		assert.FileExists(t, filename)
+		assert.Equal(t, "unchanged: "+filename+"\n", buf.String())
+	})
+	t.Run("StableImports", func(t *testing.T) {
+		tempDir := t.TempDir()
+		filename := filepath.Join(tempDir, "alias.go")
+		require.NoError(t, os.WriteFile(filename, []byte("package foo\n\nimport pkg_2 \""+TestPattern+"\"\n"), 0o644))
+		root, _ := NewTestRoot(t)
+		root.SetArgs([]string{
+			"generate",
+			"--target", "foo",
+			"--pattern", TestPattern,
+			"--file", filename,
+			"--stable-imports",
+		})
+		assert.NoError(t, root.Execute())
+		data, err := os.ReadFile(filename)
+		require.NoError(t, err)
+		assert.Contains(t, string(data), "pkg_2 \""+TestPattern+"\"")
+		assert.Contains(t, string(data), "= pkg_2.")
+
+		require.NoError(t, os.WriteFile(filename, []byte("package"), 0o644))
+		root, _ = NewTestRoot(t)
+		root.SetArgs([]string{
+			"generate",
+			"--target", "foo",
+			"--pattern", TestPattern,
+			"--file", filename,
+			"--stable-imports",
+		})
+		assert.Error(t, root.Execute())
 	})
 	t.Run("FileError", func(t *testing.T) {
 		root, buf := NewTestRoot(t)
@@ -0,0 +1,42 @@
+package internal
+
+import (
+	"fmt"
+
+	"github.com/marcozac/go-aliaser"
+	"github.com/spf13/cobra"
+)
+
+func NewMirror() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "mirror",
+		Short: "mirror a package tree into a parallel tree of alias packages",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts, err := optionsFromFlags(cmd)
+			if err != nil {
+				return err
+			}
+			files, err := aliaser.Mirror(
+				MustV(cmd.Flags().GetString("pattern")),
+				MustV(cmd.Flags().GetString("root")),
+				MustV(cmd.Flags().GetString("file")),
+				opts...,
+			)
+			for _, f := range files {
+				cmd.Println(f)
+			}
+			if err != nil {
+				return fmt.Errorf("aliaser: %w", err)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().String("pattern", "", "the pattern, in go format, of the package tree to mirror (e.g. github.com/example/lib/...)")
+	cmd.Flags().String("root", "", "the output root directory of the alias packages")
+	cmd.Flags().String("file", "alias.go", "the file name to write the aliases to in each alias package")
+	addOptionFlags(cmd)
+
+	Must(cmd.MarkFlagRequired("pattern"))
+	Must(cmd.MarkFlagRequired("root"))
+	return cmd
+}
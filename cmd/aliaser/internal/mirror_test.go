@@ -0,0 +1,51 @@
+package internal
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMirrorCmd(t *testing.T) {
+	t.Run("RequiredFlags", func(t *testing.T) {
+		root, buf := NewTestRoot(t)
+		root.SetArgs([]string{"mirror"})
+		assert.Error(t, root.Execute())
+		assert.Contains(t, buf.String(), "required flag(s) \"pattern\", \"root\" not set")
+	})
+	t.Run("Mirror", func(t *testing.T) {
+		root, buf := NewTestRoot(t)
+		out := t.TempDir()
+		root.SetArgs([]string{
+			"mirror",
+			"--pattern", "github.com/marcozac/go-aliaser/internal/testing/tree/...",
+			"--root", out,
+			"--file", "tree.go",
+		})
+		assert.NoError(t, root.Execute())
+		assert.FileExists(t, filepath.Join(out, "tree.go"))
+		assert.FileExists(t, filepath.Join(out, "go-sub", "tree.go"))
+		assert.Contains(t, buf.String(), filepath.Join(out, "go-sub", "tree.go"))
+	})
+	t.Run("OptionsError", func(t *testing.T) {
+		root, _ := NewTestRoot(t)
+		root.SetArgs([]string{
+			"mirror",
+			"--pattern", "github.com/marcozac/go-aliaser/internal/testing/tree/...",
+			"--root", t.TempDir(),
+			"--var-strategy", "invalid",
+		})
+		assert.Error(t, root.Execute())
+	})
+	t.Run("AliaserError", func(t *testing.T) {
+		root, buf := NewTestRoot(t)
+		root.SetArgs([]string{
+			"mirror",
+			"--pattern", "golang.org/x/tools/go/*",
+			"--root", t.TempDir(),
+		})
+		assert.Error(t, root.Execute())
+		assert.Contains(t, buf.String(), "aliaser: package errors:")
+	})
+}
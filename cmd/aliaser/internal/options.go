@@ -0,0 +1,121 @@
+package internal
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/marcozac/go-aliaser"
+	"github.com/spf13/cobra"
+)
+
+// addOptionFlags adds to the given command the flags used to set the
+// [aliaser.Option] values shared by the commands generating aliases.
+func addOptionFlags(cmd *cobra.Command) {
+	cmd.Flags().String("header", "", "optional header to be written at the top of the file")
+	cmd.Flags().Bool("exclude-constants", false, "exclude constants from the generated aliases")
+	cmd.Flags().Bool("exclude-variables", false, "exclude variables from the generated aliases")
+	cmd.Flags().Bool("exclude-functions", false, "exclude functions from the generated aliases")
+	cmd.Flags().Bool("exclude-types", false, "exclude types from the generated aliases")
+	cmd.Flags().StringSlice("exclude-names", nil, "exclude specific names from the generated aliases")
+	cmd.Flags().StringArray("include", nil, "alias only the objects matching a glob or /regexp/ pattern, optionally limited to a kind (e.g. func:New*), may be repeated")
+	cmd.Flags().StringArray("exclude", nil, "exclude the objects matching a glob or /regexp/ pattern, optionally limited to a kind (e.g. type:/^Internal/), may be repeated; takes precedence over --include")
+	cmd.Flags().Bool("assign-functions", false, "assign functions to variables in the generated aliases")
+	cmd.Flags().Bool("forward-methods", false, "forward the methods of the generic types generated as defined types")
+	cmd.Flags().String("var-strategy", aliaser.VarCopy.String(), "the strategy used to generate the aliases of the variables (copy, pointer, accessors)")
+	cmd.Flags().StringToString("var-strategies", nil, "the strategy used to generate the aliases of specific variables (e.g. Foo=pointer,Bar=accessors)")
+	cmd.Flags().Bool("rewrite-doc-links", false, "rewrite the doc links in the doc comments to point to the generated aliases")
+	cmd.Flags().String("on-deprecated", aliaser.DeprecatedPropagate.String(), "the policy applied to the deprecated objects (propagate, exclude, error)")
+	cmd.Flags().String("on-duplicate", aliaser.OnDuplicateSkip.String(), "the policy applied when the name of an alias is already in use (skip, replace, panic, prefix, rename, error)")
+	cmd.Flags().String("import-aliases", "all", "when the imported packages are given an explicit name (all, conflicts)")
+	cmd.Flags().StringToString("import-alias", nil, "explicit names of the imported packages by path, implying --import-aliases conflicts for the others (e.g. encoding/json=stdjson)")
+	cmd.Flags().StringSlice("reserve-names", nil, "names never used as names of the imported packages, in addition to the Go keywords, the predeclared identifiers and the names of the aliases")
+	cmd.Flags().String("rename-prefix", "", "a prefix added to the names of the aliases")
+	cmd.Flags().String("rename-suffix", "", "a suffix added to the names of the aliases")
+	cmd.Flags().StringArray("rename-regexp", nil, "a regexp replacement applied to the names of the aliases in the form REGEXP=REPLACEMENT (e.g. Client=HTTPClient), may be repeated")
+	cmd.Flags().StringToString("rename", nil, "explicit names of the aliases, taking precedence over the other renaming rules (e.g. Foo=Bar,Baz=Qux)")
+	cmd.Flags().String("dir", "", "the directory in which the packages are loaded")
+	cmd.Flags().String("target-dir", "", "the directory of the target package, whose declarations are not redeclared by the aliases")
+	cmd.Flags().StringArray("env", nil, "an environment variable in the form KEY=VALUE used to load the packages, may be repeated")
+	cmd.Flags().StringArray("build-flags", nil, "a build flag used to load the packages (e.g. -tags=foo), may be repeated")
+	cmd.Flags().String("overlay", "", "a JSON file in the format of the go build -overlay flag replacing the contents of the loaded files")
+	cmd.Flags().Bool("in-place", false, "write the files in place, restoring them on failure, instead of replacing them atomically through a temporary file (legacy mode)")
+	cmd.Flags().StringArray("platform", nil, "a target platform in the form GOOS[/GOARCH][:tag,...], may be repeated to split the platform specific aliases into separate files")
+}
+
+// optionsFromFlags returns the [aliaser.Option] values set by the flags added
+// by [addOptionFlags] to the given command.
+func optionsFromFlags(cmd *cobra.Command) ([]aliaser.Option, error) {
+	jc, err := jobConfigFromFlags(cmd)
+	if err != nil {
+		return nil, err
+	}
+	opts, err := jc.Options()
+	if err != nil {
+		return nil, err
+	}
+	return append(opts, aliaser.WithContext(cmd.Context())), nil
+}
+
+// jobConfigFromFlags returns an [aliaser.JobConfig] with the fields set by the
+// flags added by [addOptionFlags] to the given command, so that the flags and
+// the configuration files share the same conversion to options.
+func jobConfigFromFlags(cmd *cobra.Command) (*aliaser.JobConfig, error) {
+	jc := &aliaser.JobConfig{
+		Header:           MustV(cmd.Flags().GetString("header")),
+		ExcludeConstants: MustV(cmd.Flags().GetBool("exclude-constants")),
+		ExcludeVariables: MustV(cmd.Flags().GetBool("exclude-variables")),
+		ExcludeFunctions: MustV(cmd.Flags().GetBool("exclude-functions")),
+		ExcludeTypes:     MustV(cmd.Flags().GetBool("exclude-types")),
+		ExcludeNames:     MustV(cmd.Flags().GetStringSlice("exclude-names")),
+		Include:          MustV(cmd.Flags().GetStringArray("include")),
+		Exclude:          MustV(cmd.Flags().GetStringArray("exclude")),
+		AssignFunctions:  MustV(cmd.Flags().GetBool("assign-functions")),
+		ForwardMethods:   MustV(cmd.Flags().GetBool("forward-methods")),
+		VarStrategy:      MustV(cmd.Flags().GetString("var-strategy")),
+		VarStrategies:    MustV(cmd.Flags().GetStringToString("var-strategies")),
+		RewriteDocLinks:  MustV(cmd.Flags().GetBool("rewrite-doc-links")),
+		OnDeprecated:     MustV(cmd.Flags().GetString("on-deprecated")),
+		OnDuplicate:      MustV(cmd.Flags().GetString("on-duplicate")),
+		ImportAliases:    MustV(cmd.Flags().GetString("import-aliases")),
+		ImportAlias:      MustV(cmd.Flags().GetStringToString("import-alias")),
+		ReserveNames:     MustV(cmd.Flags().GetStringSlice("reserve-names")),
+		RenamePrefix:     MustV(cmd.Flags().GetString("rename-prefix")),
+		RenameSuffix:     MustV(cmd.Flags().GetString("rename-suffix")),
+		RenameRegexp:     MustV(cmd.Flags().GetStringArray("rename-regexp")),
+		Rename:           MustV(cmd.Flags().GetStringToString("rename")),
+		Dir:              MustV(cmd.Flags().GetString("dir")),
+		TargetDir:        MustV(cmd.Flags().GetString("target-dir")),
+		Env:              MustV(cmd.Flags().GetStringArray("env")),
+		BuildFlags:       MustV(cmd.Flags().GetStringArray("build-flags")),
+		Platforms:        MustV(cmd.Flags().GetStringArray("platform")),
+		InPlace:          MustV(cmd.Flags().GetBool("in-place")),
+	}
+	if name := MustV(cmd.Flags().GetString("overlay")); name != "" {
+		overlay, err := readOverlay(name)
+		if err != nil {
+			return nil, err
+		}
+		jc.Overlay = overlay
+	}
+	return jc, nil
+}
+
+// readOverlay reads the overlay file with the given name, in the format of
+// the go build -overlay flag, and returns the names of the replacement files
+// by the names of the replaced ones.
+//
+// Example:
+//
+//	{"Replace": {"pkg/gen.go": "/tmp/gen.go"}}
+func readOverlay(name string) (map[string]string, error) {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return nil, fmt.Errorf("read overlay: %w", err)
+	}
+	var of struct{ Replace map[string]string }
+	if err := json.Unmarshal(data, &of); err != nil {
+		return nil, fmt.Errorf("parse overlay %s: %w", name, err)
+	}
+	return of.Replace, nil
+}
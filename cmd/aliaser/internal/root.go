@@ -8,5 +8,8 @@ func NewRoot() *cobra.Command {
 		Short: "aliaser is a tool to generate aliases from a Go package",
 	}
 	cmd.AddCommand(NewGenerate())
+	cmd.AddCommand(NewCheck())
+	cmd.AddCommand(NewRun())
+	cmd.AddCommand(NewMirror())
 	return cmd
 }
@@ -0,0 +1,68 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/marcozac/go-aliaser"
+	"github.com/spf13/cobra"
+)
+
+func NewRun() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "run",
+		Short: "run the jobs of a configuration file, generating or checking their files",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			jobs, err := aliaser.LoadConfigFile(
+				MustV(cmd.Flags().GetString("config")),
+				aliaser.WithContext(cmd.Context()),
+			)
+			if err != nil {
+				return err
+			}
+			if MustV(cmd.Flags().GetBool("check")) {
+				return checkJobs(cmd, jobs)
+			}
+			for _, job := range jobs {
+				files, err := job.Run()
+				for _, f := range files {
+					if f.Changed {
+						cmd.Println("updated:", f.Name)
+					} else {
+						cmd.Println("unchanged:", f.Name)
+					}
+				}
+				if err != nil {
+					return fmt.Errorf("job %s: %w", job.Name, err)
+				}
+			}
+			return nil
+		},
+	}
+	cmd.Flags().String("config", "aliaser.yaml", "the configuration file listing the jobs, in YAML or JSON format")
+	cmd.Flags().Bool("check", false, "check that the generated files are up to date, printing the diff if not, instead of writing them")
+	return cmd
+}
+
+// checkJobs checks the files of all the given jobs, printing the diff of the
+// stale ones, and returns an error if any of them is stale.
+func checkJobs(cmd *cobra.Command, jobs []*aliaser.Job) error {
+	var stale []aliaser.StaleFile
+	for _, job := range jobs {
+		err := job.Check()
+		if serr := (*aliaser.StaleError)(nil); errors.As(err, &serr) {
+			stale = append(stale, serr.Files...)
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("job %s: %w", job.Name, err)
+		}
+	}
+	if len(stale) == 0 {
+		return nil
+	}
+	serr := &aliaser.StaleError{Files: stale}
+	cmd.SilenceUsage = true
+	cmd.Print(serr.Diff())
+	return serr
+}
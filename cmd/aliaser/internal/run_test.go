@@ -0,0 +1,78 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunCmd(t *testing.T) {
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	dir := t.TempDir()
+	config := filepath.Join(dir, "aliaser.yaml")
+	require.NoError(t, os.WriteFile(config, []byte(`
+defaults: &defaults
+  pattern: `+TestPattern+`
+  dir: `+wd+`
+jobs:
+  - <<: *defaults
+    target: foo
+    file: foo/alias.go
+  - <<: *defaults
+    target: bar
+    file: bar/alias.go
+    exclude-types: true
+`), 0o644))
+	foo, bar := filepath.Join(dir, "foo", "alias.go"), filepath.Join(dir, "bar", "alias.go")
+
+	root, buf := NewTestRoot(t)
+	root.SetArgs([]string{"run", "--config", config, "--check"})
+	assert.Error(t, root.Execute(), "missing files")
+	assert.Contains(t, buf.String(), "+package foo")
+	assert.Contains(t, buf.String(), "+package bar")
+	assert.NotContains(t, buf.String(), "Usage:")
+	assert.NoFileExists(t, foo)
+
+	root, buf = NewTestRoot(t)
+	root.SetArgs([]string{"run", "--config", config})
+	require.NoError(t, root.Execute())
+	assert.Equal(t, "updated: "+foo+"\nupdated: "+bar+"\n", buf.String())
+	assert.FileExists(t, foo)
+	assert.FileExists(t, bar)
+
+	root, buf = NewTestRoot(t)
+	root.SetArgs([]string{"run", "--config", config})
+	require.NoError(t, root.Execute())
+	assert.Equal(t, "unchanged: "+foo+"\nunchanged: "+bar+"\n", buf.String())
+
+	root, buf = NewTestRoot(t)
+	root.SetArgs([]string{"run", "--config", config, "--check"})
+	assert.NoError(t, root.Execute())
+	assert.Empty(t, buf.String())
+
+	t.Run("ConfigError", func(t *testing.T) {
+		root, _ := NewTestRoot(t)
+		root.SetArgs([]string{"run", "--config", filepath.Join(t.TempDir(), "aliaser.yaml")})
+		assert.Error(t, root.Execute())
+	})
+	t.Run("JobError", func(t *testing.T) {
+		config := filepath.Join(t.TempDir(), "aliaser.yaml")
+		require.NoError(t, os.WriteFile(config, []byte(`
+jobs:
+  - target: foo
+    pattern: `+TestPattern+`
+    dir: `+wd+`
+    file: `+t.TempDir()+`
+`), 0o644))
+		for _, args := range [][]string{{"run"}, {"run", "--check"}} {
+			root, buf := NewTestRoot(t)
+			root.SetArgs(append(args, "--config", config))
+			assert.Error(t, root.Execute())
+			assert.Contains(t, buf.String(), "job ")
+		}
+	})
+}
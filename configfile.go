@@ -0,0 +1,394 @@
+package aliaser
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/marcozac/go-aliaser/importer"
+	"gopkg.in/yaml.v3"
+)
+
+// ConfigFile is the schema of a configuration file, e.g. "aliaser.yaml",
+// listing the jobs generating the aliases of a module, so that they share
+// the same options instead of repeating them in each go:generate directive.
+// It is written in YAML or in JSON, that is a subset of YAML, and it is
+// parsed by [ParseConfigFile].
+//
+// The options shared by several jobs can be declared once with the YAML
+// anchors and merge keys.
+//
+// Example:
+//
+//	defaults: &defaults
+//	  import-aliases: conflicts
+//	  on-duplicate: prefix
+//	jobs:
+//	  - <<: *defaults
+//	    target: foo
+//	    pattern: github.com/example/foo
+//	    file: foo/alias.go
+//	  - <<: *defaults
+//	    target: bar
+//	    patterns: [github.com/example/bar/api, github.com/example/bar/types]
+//	    file: bar/alias.go
+//	    exclude: ["type:/^Internal/"]
+type ConfigFile struct {
+	// Defaults is an optional job ignored by the loader, whose only purpose
+	// is to hold the anchor of the shared options.
+	Defaults *JobConfig `yaml:"defaults,omitempty"`
+
+	// Jobs are the jobs to run, in order.
+	Jobs []JobConfig `yaml:"jobs"`
+}
+
+// JobConfig is the configuration of a job of a [ConfigFile]. Its fields match
+// the flags of the aliaser generate command, and the [Option] values they
+// set, in both name and format, since the CLI converts its flags to options
+// through a JobConfig as well (see [JobConfig.Options]).
+//
+// The relative paths, such as the output file and the directories, are
+// relative to the directory of the configuration file, that is also the
+// default directory in which the packages are loaded (see [WithDir]).
+type JobConfig struct {
+	// Name is an optional name of the job, used in the error messages.
+	// Default: the output file.
+	Name string `yaml:"name,omitempty"`
+
+	// Target is the name of the target package (see [Config.TargetPackage]).
+	Target string `yaml:"target"`
+
+	// Pattern and Patterns are the patterns of the packages to alias (see
+	// [Config.Pattern] and [Config.Patterns]).
+	Pattern  string   `yaml:"pattern,omitempty"`
+	Patterns []string `yaml:"patterns,omitempty"`
+
+	// File is the name of the output file.
+	File string `yaml:"file"`
+
+	Header           string            `yaml:"header,omitempty"`
+	GoVersion        string            `yaml:"go-version,omitempty"`
+	ExcludeConstants bool              `yaml:"exclude-constants,omitempty"`
+	ExcludeVariables bool              `yaml:"exclude-variables,omitempty"`
+	ExcludeFunctions bool              `yaml:"exclude-functions,omitempty"`
+	ExcludeTypes     bool              `yaml:"exclude-types,omitempty"`
+	ExcludeNames     []string          `yaml:"exclude-names,omitempty"`
+	Include          []string          `yaml:"include,omitempty"`
+	Exclude          []string          `yaml:"exclude,omitempty"`
+	AssignFunctions  bool              `yaml:"assign-functions,omitempty"`
+	ForwardMethods   bool              `yaml:"forward-methods,omitempty"`
+	VarStrategy      string            `yaml:"var-strategy,omitempty"`
+	VarStrategies    map[string]string `yaml:"var-strategies,omitempty"`
+	RewriteDocLinks  bool              `yaml:"rewrite-doc-links,omitempty"`
+	OnDeprecated     string            `yaml:"on-deprecated,omitempty"`
+	OnDuplicate      string            `yaml:"on-duplicate,omitempty"`
+	ImportAliases    string            `yaml:"import-aliases,omitempty"`
+	ImportAlias      map[string]string `yaml:"import-alias,omitempty"`
+	StableImports    bool              `yaml:"stable-imports,omitempty"`
+	ReserveNames     []string          `yaml:"reserve-names,omitempty"`
+	RenamePrefix     string            `yaml:"rename-prefix,omitempty"`
+	RenameSuffix     string            `yaml:"rename-suffix,omitempty"`
+	RenameRegexp     []string          `yaml:"rename-regexp,omitempty"`
+	Rename           map[string]string `yaml:"rename,omitempty"`
+	MergePackages    bool              `yaml:"merge-packages,omitempty"`
+	Dir              string            `yaml:"dir,omitempty"`
+	TargetDir        string            `yaml:"target-dir,omitempty"`
+	Env              []string          `yaml:"env,omitempty"`
+	BuildFlags       []string          `yaml:"build-flags,omitempty"`
+	Platforms        []string          `yaml:"platforms,omitempty"`
+	InPlace          bool              `yaml:"in-place,omitempty"`
+
+	// Overlay maps the names of the files to replace while loading the
+	// packages to the names of the replacement files (see [WithOverlay]).
+	Overlay map[string]string `yaml:"overlay,omitempty"`
+}
+
+// Job is a job of a configuration file loaded by [LoadConfigFile]: an
+// [Aliaser] ready to generate the aliases into the output file.
+type Job struct {
+	*Aliaser
+
+	// Name is the name of the job (see [JobConfig.Name]).
+	Name string
+
+	// File is the name of the output file, relative to the current directory
+	// or absolute.
+	File string
+}
+
+// Run generates the aliases into the output file of the job, as by
+// [Aliaser.GenerateFiles].
+func (j *Job) Run() ([]GeneratedFile, error) {
+	return j.GenerateFiles(j.File)
+}
+
+// Check checks the output file of the job, as by [Aliaser.Check].
+func (j *Job) Check() error {
+	return j.Aliaser.Check(j.File)
+}
+
+// ParseConfigFile parses the configuration file with the given name (see
+// [ConfigFile]), resolving the relative paths of the jobs against its
+// directory. The unknown fields are reported as errors, to catch typos, as
+// well as the jobs without a target or an output file ([ErrInvalidJob]).
+func ParseConfigFile(name string) (*ConfigFile, error) {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return nil, fmt.Errorf("read config file: %w", err)
+	}
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	cf := new(ConfigFile)
+	if err := dec.Decode(cf); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("parse config file %s: %w", name, err)
+	}
+	if len(cf.Jobs) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrNoJobs, name)
+	}
+	dir := filepath.Dir(name)
+	for i := range cf.Jobs {
+		if err := cf.Jobs[i].validate(); err != nil {
+			return nil, fmt.Errorf("%w: %s: jobs[%d]: %w", ErrInvalidJob, name, i, err)
+		}
+		cf.Jobs[i].resolve(dir)
+	}
+	return cf, nil
+}
+
+// LoadConfigFile parses the configuration file with the given name (see
+// [ParseConfigFile]) and returns its jobs, in order, with the aliasers loaded
+// with the options of each job, followed by the given ones.
+//
+// Example:
+//
+//	jobs, err := aliaser.LoadConfigFile("aliaser.yaml")
+//	if err != nil {
+//		// ...
+//	}
+//	for _, job := range jobs {
+//		if _, err := job.Run(); err != nil {
+//			// ...
+//		}
+//	}
+func LoadConfigFile(name string, opts ...Option) ([]*Job, error) {
+	cf, err := ParseConfigFile(name)
+	if err != nil {
+		return nil, err
+	}
+	jobs := make([]*Job, 0, len(cf.Jobs))
+	for _, jc := range cf.Jobs {
+		a, err := jc.New(opts...)
+		if err != nil {
+			return nil, fmt.Errorf("job %s: %w", jc.name(), err)
+		}
+		jobs = append(jobs, &Job{Aliaser: a, Name: jc.name(), File: jc.File})
+	}
+	return jobs, nil
+}
+
+// New returns a new [Aliaser] configured by the job, with the given options
+// applied after the ones of the job.
+func (jc *JobConfig) New(opts ...Option) (*Aliaser, error) {
+	jopts, err := jc.Options()
+	if err != nil {
+		return nil, err
+	}
+	return New(&Config{
+		TargetPackage: jc.Target,
+		Pattern:       jc.Pattern,
+		Patterns:      jc.Patterns,
+		GoVersion:     jc.GoVersion,
+	}, append(jopts, opts...)...)
+}
+
+// Options returns the [Option] values set by the job, or an error if any of
+// them is invalid, e.g. an unknown strategy name.
+func (jc *JobConfig) Options() ([]Option, error) {
+	opts := []Option{
+		ExcludeConstants(jc.ExcludeConstants),
+		ExcludeVariables(jc.ExcludeVariables),
+		ExcludeFunctions(jc.ExcludeFunctions),
+		ExcludeTypes(jc.ExcludeTypes),
+		ExcludeNames(jc.ExcludeNames...),
+		AssignFunctions(jc.AssignFunctions),
+		ForwardMethods(jc.ForwardMethods),
+		RewriteDocLinks(jc.RewriteDocLinks),
+		MergePackages(jc.MergePackages),
+		WithDir(jc.Dir),
+		WithTargetDir(jc.TargetDir),
+		WithEnv(jc.Env...),
+		WithBuildFlags(jc.BuildFlags...),
+		ReserveNames(jc.ReserveNames...),
+		InPlaceWrites(jc.InPlace),
+		RenamePrefix(jc.RenamePrefix),
+		RenameSuffix(jc.RenameSuffix),
+		RenameNames(jc.Rename),
+	}
+	if jc.Header != "" {
+		opts = append(opts, WithHeader(jc.Header))
+	}
+	for _, s := range []struct {
+		name  string
+		parse func(string) (Option, error)
+	}{
+		{jc.VarStrategy, func(s string) (Option, error) {
+			vs, err := ParseVarStrategy(s)
+			return WithVarStrategy(vs), err
+		}},
+		{jc.OnDeprecated, func(s string) (Option, error) {
+			dp, err := ParseDeprecatedPolicy(s)
+			return OnDeprecated(dp), err
+		}},
+		{jc.OnDuplicate, func(s string) (Option, error) {
+			dup, err := ParseDuplicatePolicy(s)
+			return OnDuplicate(dup), err
+		}},
+	} {
+		if s.name == "" {
+			continue
+		}
+		opt, err := s.parse(s.name)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, opt)
+	}
+	for name, strategy := range jc.VarStrategies {
+		vs, err := ParseVarStrategy(strategy)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, WithVarStrategy(vs, name))
+	}
+	for _, f := range []struct {
+		patterns []string
+		option   func(Kind, ...string) Option
+	}{
+		{jc.Include, IncludeMatching},
+		{jc.Exclude, ExcludeMatching},
+	} {
+		for _, s := range f.patterns {
+			kind, pattern := KindAny, s
+			if prefix, rest, ok := strings.Cut(s, ":"); ok {
+				if k, err := ParseKind(prefix); err == nil {
+					kind, pattern = k, rest
+				}
+			}
+			if pattern == "" {
+				return nil, fmt.Errorf("empty name filter pattern: %q", s)
+			}
+			opts = append(opts, f.option(kind, pattern))
+		}
+	}
+	for _, s := range jc.RenameRegexp {
+		i := strings.LastIndex(s, "=")
+		if i < 0 {
+			return nil, fmt.Errorf("invalid rename regexp %q: expected REGEXP=REPLACEMENT", s)
+		}
+		re, err := regexp.Compile(s[:i])
+		if err != nil {
+			return nil, fmt.Errorf("invalid rename regexp %q: %w", s, err)
+		}
+		opts = append(opts, RenameRegexp(re, s[i+1:]))
+	}
+	for _, s := range jc.Platforms {
+		p, err := ParsePlatform(s)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, WithPlatforms(p))
+	}
+	is, err := jc.importStrategy()
+	if err != nil {
+		return nil, err
+	}
+	opts = append(opts, WithImportStrategy(is))
+	if jc.StableImports && jc.File != "" {
+		aliases, err := importer.ParseAliases(jc.File, nil)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("import aliases: %w", err)
+		}
+		opts = append(opts, WithImportAliases(aliases))
+	}
+	if len(jc.Overlay) > 0 {
+		overlay := make(map[string][]byte, len(jc.Overlay))
+		for file, replacement := range jc.Overlay {
+			abs, err := filepath.Abs(file)
+			if err != nil {
+				return nil, fmt.Errorf("abs: %w", err)
+			}
+			if overlay[abs], err = os.ReadFile(replacement); err != nil {
+				return nil, fmt.Errorf("read overlay: %w", err)
+			}
+		}
+		opts = append(opts, WithOverlay(overlay))
+	}
+	return opts, nil
+}
+
+// importStrategy returns the [importer.Strategy] set by the import-aliases
+// and import-alias fields.
+func (jc *JobConfig) importStrategy() (importer.Strategy, error) {
+	if len(jc.ImportAlias) > 0 {
+		return importer.AliasPinned(jc.ImportAlias), nil
+	}
+	switch jc.ImportAliases {
+	case "", "all":
+		return importer.AliasAll{}, nil
+	case "conflicts":
+		return importer.AliasConflicts{}, nil
+	}
+	return nil, fmt.Errorf("invalid import aliases: %q", jc.ImportAliases)
+}
+
+// validate returns an error if any of the required fields of the job is
+// empty.
+func (jc *JobConfig) validate() error {
+	switch {
+	case jc.Target == "":
+		return errors.New("empty target")
+	case jc.File == "":
+		return errors.New("empty file")
+	}
+	return nil
+}
+
+// resolve resolves the relative paths of the job against the given directory,
+// that is also set as the directory in which the packages are loaded, if not
+// set.
+func (jc *JobConfig) resolve(dir string) {
+	join := func(name string) string {
+		if name == "" || filepath.IsAbs(name) {
+			return name
+		}
+		return filepath.Join(dir, name)
+	}
+	jc.File = join(jc.File)
+	jc.TargetDir = join(jc.TargetDir)
+	if jc.Dir == "" {
+		jc.Dir = dir
+	} else {
+		jc.Dir = join(jc.Dir)
+	}
+	if len(jc.Overlay) > 0 {
+		overlay := make(map[string]string, len(jc.Overlay))
+		for file, replacement := range jc.Overlay {
+			overlay[join(file)] = join(replacement)
+		}
+		jc.Overlay = overlay
+	}
+}
+
+// name returns the name of the job, or its output file if not set.
+func (jc *JobConfig) name() string {
+	if jc.Name != "" {
+		return jc.Name
+	}
+	return jc.File
+}
@@ -0,0 +1,185 @@
+package aliaser
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/marcozac/go-aliaser/importer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeConfigFile writes a configuration file with the given content into a
+// temporary directory and returns its name.
+func writeConfigFile(t *testing.T, content string) string {
+	t.Helper()
+	name := filepath.Join(t.TempDir(), "aliaser.yaml")
+	require.NoError(t, os.WriteFile(name, []byte(content), 0o644))
+	return name
+}
+
+func TestParseConfigFile(t *testing.T) {
+	name := writeConfigFile(t, `
+defaults: &defaults
+  import-aliases: conflicts
+  on-duplicate: prefix
+jobs:
+  - <<: *defaults
+    target: foo
+    pattern: example.com/foo
+    file: foo/alias.go
+    overlay:
+      foo/gen.go: /tmp/gen.go
+  - <<: *defaults
+    name: bar
+    target: bar
+    patterns: [example.com/bar/api, example.com/bar/types]
+    file: /abs/bar/alias.go
+    dir: mod
+    exclude: ["type:/^Internal/"]
+`)
+	dir := filepath.Dir(name)
+	cf, err := ParseConfigFile(name)
+	require.NoError(t, err)
+	require.Len(t, cf.Jobs, 2)
+
+	foo := cf.Jobs[0]
+	assert.Equal(t, "foo", foo.Target)
+	assert.Equal(t, "conflicts", foo.ImportAliases)
+	assert.Equal(t, "prefix", foo.OnDuplicate)
+	assert.Equal(t, filepath.Join(dir, "foo/alias.go"), foo.File)
+	assert.Equal(t, dir, foo.Dir)
+	assert.Equal(t, map[string]string{filepath.Join(dir, "foo/gen.go"): "/tmp/gen.go"}, foo.Overlay)
+	assert.Equal(t, foo.File, foo.name())
+
+	bar := cf.Jobs[1]
+	assert.Equal(t, []string{"example.com/bar/api", "example.com/bar/types"}, bar.Patterns)
+	assert.Equal(t, "/abs/bar/alias.go", bar.File)
+	assert.Equal(t, filepath.Join(dir, "mod"), bar.Dir)
+	assert.Equal(t, []string{"type:/^Internal/"}, bar.Exclude)
+	assert.Equal(t, "bar", bar.name())
+
+	t.Run("JSON", func(t *testing.T) {
+		name := writeConfigFile(t, `{"jobs": [{"target": "foo", "pattern": "example.com/foo", "file": "alias.go"}]}`)
+		cf, err := ParseConfigFile(name)
+		require.NoError(t, err)
+		require.Len(t, cf.Jobs, 1)
+		assert.Equal(t, "example.com/foo", cf.Jobs[0].Pattern)
+	})
+	t.Run("UnknownField", func(t *testing.T) {
+		_, err := ParseConfigFile(writeConfigFile(t, "jobs:\n  - target: foo\n    patern: example.com/foo\n"))
+		assert.ErrorContains(t, err, "patern")
+	})
+	t.Run("InvalidJob", func(t *testing.T) {
+		for content, msg := range map[string]string{
+			"jobs:\n  - target: foo\n    file: foo.go\n  - target: bar\n    pattern: example.com/bar\n": "jobs[1]: empty file",
+			"jobs:\n  - pattern: example.com/foo\n    file: foo.go\n":                                   "jobs[0]: empty target",
+		} {
+			_, err := ParseConfigFile(writeConfigFile(t, content))
+			assert.ErrorIs(t, err, ErrInvalidJob)
+			assert.ErrorContains(t, err, msg)
+		}
+	})
+	t.Run("NoJobs", func(t *testing.T) {
+		_, err := ParseConfigFile(writeConfigFile(t, ""))
+		assert.ErrorIs(t, err, ErrNoJobs)
+	})
+	t.Run("NotExist", func(t *testing.T) {
+		_, err := ParseConfigFile(filepath.Join(t.TempDir(), "aliaser.yaml"))
+		assert.ErrorIs(t, err, os.ErrNotExist)
+	})
+}
+
+func TestJobConfigOptions(t *testing.T) {
+	jc := &JobConfig{
+		Header:        "// Code generated by test. DO NOT EDIT.",
+		VarStrategy:   "pointer",
+		VarStrategies: map[string]string{"Foo": "accessors"},
+		OnDeprecated:  "exclude",
+		OnDuplicate:   "error",
+		ImportAlias:   map[string]string{"encoding/json": "stdjson"},
+		Include:       []string{"func:New*"},
+		Exclude:       []string{"/^a:b$/"},
+		RenameRegexp:  []string{"Client=HTTPClient"},
+		Platforms:     []string{"linux/amd64"},
+	}
+	opts, err := jc.Options()
+	require.NoError(t, err)
+	c := (&Config{}).setDefaults().applyOptions(opts...)
+	assert.Equal(t, jc.Header, c.Header)
+	assert.Equal(t, VarPointer, c.varStrategy)
+	assert.Equal(t, DeprecatedExclude, c.onDeprecated)
+	assert.Equal(t, OnDuplicateError, c.onDuplicate)
+	assert.Equal(t, importer.AliasPinned(jc.ImportAlias), c.importStrategy)
+	assert.Equal(t, []Platform{{GOOS: "linux", GOARCH: "amd64"}}, c.platforms)
+
+	for _, tt := range []struct {
+		name string
+		jc   JobConfig
+	}{
+		{"VarStrategy", JobConfig{VarStrategy: "invalid"}},
+		{"VarStrategies", JobConfig{VarStrategies: map[string]string{"Foo": "invalid"}}},
+		{"OnDeprecated", JobConfig{OnDeprecated: "invalid"}},
+		{"OnDuplicate", JobConfig{OnDuplicate: "invalid"}},
+		{"ImportAliases", JobConfig{ImportAliases: "invalid"}},
+		{"EmptyFilter", JobConfig{Include: []string{"func:"}}},
+		{"RenameRegexp", JobConfig{RenameRegexp: []string{"Client"}}},
+		{"InvalidRenameRegexp", JobConfig{RenameRegexp: []string{"(=x"}}},
+		{"Platform", JobConfig{Platforms: []string{""}}},
+		{"Overlay", JobConfig{Overlay: map[string]string{"gen.go": filepath.Join(t.TempDir(), "gen.go")}}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.jc.Options()
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestLoadConfigFile(t *testing.T) {
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	name := writeConfigFile(t, `
+jobs:
+  - target: out
+    pattern: `+TestPattern+`
+    file: out/alias.go
+    dir: `+wd+`
+    stable-imports: true
+  - target: consts
+    pattern: `+TestPattern+`
+    file: consts/alias.go
+    dir: `+wd+`
+    exclude-variables: true
+    exclude-functions: true
+    exclude-types: true
+`)
+	jobs, err := LoadConfigFile(name)
+	require.NoError(t, err)
+	require.Len(t, jobs, 2)
+	for _, job := range jobs {
+		assert.ErrorIs(t, job.Check(), ErrStale)
+		files, err := job.Run()
+		require.NoError(t, err)
+		require.Len(t, files, 1)
+		assert.True(t, files[0].Changed)
+		assert.Equal(t, job.File, job.Name)
+		assert.NoError(t, job.Check())
+	}
+	assert.NotEqual(t, len(jobs[0].Types()), len(jobs[1].Types()))
+
+	t.Run("Options", func(t *testing.T) {
+		jobs, err := LoadConfigFile(name, ExcludeConstants(true))
+		require.NoError(t, err)
+		assert.Empty(t, jobs[1].Constants())
+	})
+	t.Run("Error", func(t *testing.T) {
+		_, err := LoadConfigFile(writeConfigFile(t, "jobs:\n  - name: foo\n    target: foo\n    file: foo.go\n"))
+		assert.ErrorIs(t, err, ErrEmptyPattern)
+		assert.ErrorContains(t, err, "job foo")
+	})
+	t.Run("ParseError", func(t *testing.T) {
+		_, err := LoadConfigFile(writeConfigFile(t, "jobs: foo\n"))
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,278 @@
+package aliaser
+
+import (
+	"go/ast"
+	"go/doc/comment"
+	"go/token"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// objectDoc is the doc comment of an object declared in a loaded package.
+type objectDoc struct {
+	// text is the text of the comment, as returned by [ast.CommentGroup.Text].
+	text string
+
+	// pkg is the package where the object is declared.
+	pkg *types.Package
+
+	// imports maps the names of the packages imported by the file where the
+	// object is declared to their paths.
+	imports map[string]string
+}
+
+// docFinder is the interface implemented by types that can find the doc
+// comment of an object.
+type docFinder interface {
+	// findDoc returns the doc comment of the given object, formatted as a
+	// sequence of line comments, or an empty string if it has none.
+	findDoc(obj types.Object) string
+
+	// findDeprecation returns the deprecation notice of the given object,
+	// formatted as a sequence of line comments, or an empty string if it is
+	// not deprecated.
+	findDeprecation(obj types.Object) string
+}
+
+// collectDocs collects the doc comments of the package-level objects and of
+// the methods declared in the given package, if its syntax is loaded.
+func (a *Aliaser) collectDocs(pkg *packages.Package) {
+	names := make(map[string]string, len(pkg.Types.Imports()))
+	for _, p := range pkg.Types.Imports() {
+		names[p.Path()] = p.Name()
+	}
+	for _, f := range pkg.Syntax {
+		imports := fileImports(f, names)
+		add := func(ident *ast.Ident, doc *ast.CommentGroup) {
+			if doc != nil {
+				a.docs[ident.Pos()] = &objectDoc{doc.Text(), pkg.Types, imports}
+			}
+		}
+		for _, decl := range f.Decls {
+			switch decl := decl.(type) {
+			case *ast.FuncDecl:
+				add(decl.Name, decl.Doc)
+			case *ast.GenDecl:
+				for _, spec := range decl.Specs {
+					addSpecDoc(spec, decl, add)
+				}
+			}
+		}
+	}
+}
+
+// addSpecDoc calls add for each name declared by the given spec with its doc
+// comment. As in [go/doc], if the spec has no doc comment and it is the only
+// one in the declaration, the declaration doc comment is used instead.
+func addSpecDoc(spec ast.Spec, decl *ast.GenDecl, add func(*ast.Ident, *ast.CommentGroup)) {
+	switch spec := spec.(type) {
+	case *ast.ValueSpec:
+		doc := spec.Doc
+		if doc == nil && len(decl.Specs) == 1 {
+			doc = decl.Doc
+		}
+		for _, name := range spec.Names {
+			add(name, doc)
+		}
+	case *ast.TypeSpec:
+		doc := spec.Doc
+		if doc == nil && len(decl.Specs) == 1 {
+			doc = decl.Doc
+		}
+		add(spec.Name, doc)
+	}
+}
+
+// fileImports returns a map of the names of the packages imported by the given
+// file to their paths. The names of the packages without an explicit name are
+// resolved using the given map of paths to names.
+func fileImports(f *ast.File, names map[string]string) map[string]string {
+	imports := make(map[string]string, len(f.Imports))
+	for _, spec := range f.Imports {
+		path := strings.Trim(spec.Path.Value, "`\"")
+		name := names[path]
+		if spec.Name != nil {
+			name = spec.Name.Name
+		}
+		if name != "" && name != "_" && name != "." {
+			imports[name] = path
+		}
+	}
+	return imports
+}
+
+// findDoc implements the [docFinder] interface. If [RewriteDocLinks] is
+// enabled, the doc links are rewritten to point to the aliases.
+func (a *Aliaser) findDoc(obj types.Object) string {
+	od, ok := a.docs[obj.Pos()]
+	if !ok {
+		return ""
+	}
+	p := &comment.Parser{
+		LookupPackage: func(name string) (string, bool) {
+			path, ok := od.imports[name]
+			return path, ok
+		},
+		LookupSym: func(recv, name string) bool {
+			return lookupSym(od.pkg, recv, name)
+		},
+	}
+	d := p.Parse(od.text)
+	if a.rewriteDocLinks {
+		walkDocLinks(d.Content, func(dl *comment.DocLink) {
+			a.rewriteDocLink(dl, od.pkg)
+		})
+	}
+	pr := &comment.Printer{}
+	return lineComment(string(pr.Comment(d)))
+}
+
+// lineComment returns the given text formatted as a sequence of line comments.
+// Empty lines are formatted as "//", while the lines starting with a tab, as
+// the ones in a code block, are not separated from the comment marker by a
+// space.
+func lineComment(text string) string {
+	lines := strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+	for i, line := range lines {
+		switch {
+		case line == "", strings.HasPrefix(line, "\t"):
+			lines[i] = "//" + line
+		default:
+			lines[i] = "// " + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// findDeprecation implements the [docFinder] interface.
+func (a *Aliaser) findDeprecation(obj types.Object) string {
+	if notice := a.deprecationNotice(obj); notice != "" {
+		return lineComment(notice)
+	}
+	return ""
+}
+
+// deprecationNotice returns the deprecation notice in the doc comment of the
+// given object, or an empty string if it is not deprecated.
+func (a *Aliaser) deprecationNotice(obj types.Object) string {
+	od, ok := a.docs[obj.Pos()]
+	if !ok {
+		return ""
+	}
+	return deprecationNotice(od.text)
+}
+
+// deprecationNotice returns the first paragraph of the given doc comment text
+// starting with "Deprecated: ", or an empty string if there is none.
+func deprecationNotice(text string) string {
+	for _, p := range strings.Split(text, "\n\n") {
+		if strings.HasPrefix(p, "Deprecated: ") {
+			return strings.TrimSpace(p)
+		}
+	}
+	return ""
+}
+
+// lookupSym reports whether the symbol with the given name, or the method
+// with the given name of the recv type, exists in the given package.
+func lookupSym(pkg *types.Package, recv, name string) bool {
+	if recv == "" {
+		return pkg.Scope().Lookup(name) != nil
+	}
+	tn, ok := pkg.Scope().Lookup(recv).(*types.TypeName)
+	if !ok {
+		return false
+	}
+	obj, _, _ := types.LookupFieldOrMethod(tn.Type(), true, pkg, name)
+	_, ok = obj.(*types.Func)
+	return ok
+}
+
+// walkDocLinks calls fn for each doc link in the given blocks.
+func walkDocLinks(blocks []comment.Block, fn func(*comment.DocLink)) {
+	var walkText func([]comment.Text)
+	walkText = func(texts []comment.Text) {
+		for _, t := range texts {
+			switch t := t.(type) {
+			case *comment.DocLink:
+				fn(t)
+			case *comment.Link:
+				walkText(t.Text)
+			}
+		}
+	}
+	for _, b := range blocks {
+		switch b := b.(type) {
+		case *comment.Paragraph:
+			walkText(b.Text)
+		case *comment.Heading:
+			walkText(b.Text)
+		case *comment.List:
+			for _, item := range b.Items {
+				walkDocLinks(item.Content, fn)
+			}
+		}
+	}
+}
+
+// rewriteDocLink rewrites the text of the given doc link, found in the doc
+// comment of an object declared in pkg, to point to the alias of the linked
+// symbol, if any, or to the original symbol through the package alias used
+// in the generated code. Links to packages not imported by the generated code
+// or using a full import path are left unchanged.
+func (a *Aliaser) rewriteDocLink(dl *comment.DocLink, pkg *types.Package) {
+	orig := plainText(dl.Text)
+	if strings.Contains(orig, "/") {
+		return
+	}
+	star := ""
+	if strings.HasPrefix(orig, "*") {
+		star = "*"
+	}
+	sym := dl.Name
+	if dl.Recv != "" {
+		sym = dl.Recv + "." + dl.Name
+	}
+	var text string
+	switch path := dl.ImportPath; {
+	case path == "" || path == pkg.Path():
+		head, _, _ := strings.Cut(sym, ".")
+		if !token.IsExported(head) {
+			return
+		}
+		if alias, ok := a.aliasNameOf(pkg.Scope().Lookup(head)); ok {
+			text = alias + strings.TrimPrefix(sym, head)
+		} else {
+			text = a.aliasOf(pkg.Path()) + "." + sym
+		}
+	default:
+		alias := a.aliasOf(path)
+		if alias == "" {
+			return
+		}
+		text = alias
+		if sym != "" {
+			text += "." + sym
+		}
+	}
+	dl.Text = []comment.Text{comment.Plain(star + text)}
+}
+
+// aliasOf returns the alias of the package with the given path in the
+// generated code, or an empty string if it is not imported.
+func (a *Aliaser) aliasOf(path string) string {
+	return a.AliasedImports()[path]
+}
+
+// plainText returns the concatenation of the plain text in the given texts.
+func plainText(texts []comment.Text) string {
+	var sb strings.Builder
+	for _, t := range texts {
+		if p, ok := t.(comment.Plain); ok {
+			sb.WriteString(string(p))
+		}
+	}
+	return sb.String()
+}
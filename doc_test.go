@@ -0,0 +1,66 @@
+package aliaser
+
+import (
+	"go/doc/comment"
+	"go/types"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/tools/go/packages"
+)
+
+func TestLineComment(t *testing.T) {
+	assert.Equal(t, "// Foo does nothing.\n//\n//\tFoo()", lineComment("Foo does nothing.\n\n\tFoo()\n"))
+}
+
+func TestDeprecationNotice(t *testing.T) {
+	assert.Equal(t, "Deprecated: use Bar.", deprecationNotice("Foo does nothing.\n\nDeprecated: use Bar.\n"))
+	assert.Equal(t, "Deprecated: use Bar\ninstead.", deprecationNotice("Deprecated: use Bar\ninstead.\n\nFoo does nothing.\n"))
+	assert.Empty(t, deprecationNotice("Foo does nothing. Deprecated: no.\n"))
+}
+
+func TestLookupSym(t *testing.T) {
+	LoadedPackageHelper(t, func(t *testing.T, p *packages.Package) {
+		assert.True(t, lookupSym(p.Types, "", "A"))
+		assert.False(t, lookupSym(p.Types, "", "Unknown"))
+		assert.True(t, lookupSym(p.Types, "P", "Foo"))
+		assert.False(t, lookupSym(p.Types, "P", "N"))   // field
+		assert.False(t, lookupSym(p.Types, "A", "Foo")) // not a type
+	})
+}
+
+func TestRewriteDocLink(t *testing.T) {
+	rewrite := func(a *Aliaser, dl *comment.DocLink, pkg *types.Package) string {
+		a.rewriteDocLink(dl, pkg)
+		return plainText(dl.Text)
+	}
+	t.Run("Unchanged", AliaserTest(func(t *testing.T, a *Aliaser) {
+		pkg := a.Constants()[0].Pkg()
+		assert.Equal(t, "encoding/json.Marshaler", rewrite(a, &comment.DocLink{
+			Text:       []comment.Text{comment.Plain("encoding/json.Marshaler")},
+			ImportPath: "encoding/json",
+			Name:       "Marshaler",
+		}, pkg), "full import path")
+		assert.Equal(t, "a", rewrite(a, &comment.DocLink{
+			Text: []comment.Text{comment.Plain("a")},
+			Name: "a",
+		}, pkg), "not exported")
+		assert.Equal(t, "fmt.Stringer", rewrite(a, &comment.DocLink{
+			Text:       []comment.Text{comment.Plain("fmt.Stringer")},
+			ImportPath: "fmt",
+			Name:       "Stringer",
+		}, pkg), "not imported")
+	}))
+	t.Run("Rewritten", AliaserTest(func(t *testing.T, a *Aliaser) {
+		pkg := a.Constants()[0].Pkg()
+		assert.Equal(t, "*pkg.D", rewrite(a, &comment.DocLink{
+			Text: []comment.Text{comment.Plain("*D")},
+			Name: "D",
+		}, pkg))
+		assert.Equal(t, "P.Foo", rewrite(a, &comment.DocLink{
+			Text: []comment.Text{comment.Plain("P.Foo")},
+			Recv: "P",
+			Name: "Foo",
+		}, pkg))
+	}, ExcludeNames("D")))
+}
@@ -2,6 +2,7 @@
 
 import (
 	"errors"
+	"go/types"
 	"strings"
 
 	"golang.org/x/tools/go/packages"
@@ -16,6 +17,45 @@
 
 	// ErrEmptyPattern is returned when the given pattern is empty.
 	ErrEmptyPattern = errors.New("empty pattern")
+
+	// ErrEmptyRoot is returned when the given output root is empty.
+	ErrEmptyRoot = errors.New("empty root")
+
+	// ErrNoPackages is returned when the patterns match no package, e.g. by
+	// [New] or by [Mirror] when none of the loaded packages is in the tree
+	// matched by the pattern.
+	ErrNoPackages = errors.New("no packages")
+
+	// ErrDeprecated is returned when the loaded package has deprecated objects
+	// to alias and the [DeprecatedError] policy is used.
+	ErrDeprecated = errors.New("deprecated objects")
+
+	// ErrInvalidPlatform is returned when a target platform is invalid, e.g.
+	// it has neither an operating system nor build tags.
+	ErrInvalidPlatform = errors.New("invalid platform")
+
+	// ErrInvalidNamePattern is returned when a pattern of a name filter is
+	// neither a valid glob nor a valid regular expression.
+	ErrInvalidNamePattern = errors.New("invalid name pattern")
+
+	// ErrInvalidAliasName is returned when a [Transformer] sets a name that
+	// is not a valid identifier for an alias.
+	ErrInvalidAliasName = errors.New("invalid alias name")
+
+	// ErrDuplicate is wrapped by the [DuplicateError] returned when the name
+	// of an alias is already in use and the [OnDuplicateError] policy is used.
+	ErrDuplicate = errors.New("duplicate object names")
+
+	// ErrStale is wrapped by the [StaleError] returned by [Aliaser.Check]
+	// when the generated files differ from the ones on disk.
+	ErrStale = errors.New("stale generated files")
+
+	// ErrNoJobs is returned when a configuration file has no jobs.
+	ErrNoJobs = errors.New("no jobs")
+
+	// ErrInvalidJob is returned when a job of a configuration file lacks a
+	// required field, such as the target package or the output file.
+	ErrInvalidJob = errors.New("invalid job")
 )
 
 // PackagesErrors is a slice of [packages.Error] as returned by
@@ -32,3 +72,90 @@ func (e PackagesErrors) Error() string {
 	}
 	return strings.Join(msgs, "; ")
 }
+
+// Duplicate is an object skipped by the [OnDuplicateError] policy because
+// the name of its alias is already in use.
+type Duplicate struct {
+	// Name is the name of the alias.
+	Name string
+
+	// Object is the skipped object.
+	Object types.Object
+
+	// Target reports whether the name is declared in the target package (see
+	// [WithTargetDir]) rather than by another alias.
+	Target bool
+}
+
+// String returns the name of the alias, followed by a note if it is declared
+// in the target package.
+func (d Duplicate) String() string {
+	if d.Target {
+		return d.Name + ": declared in the target package"
+	}
+	return d.Name
+}
+
+// DuplicateError is the error returned when the [OnDuplicateError] policy is
+// used and the names of the aliases of some objects are already in use. It
+// wraps [ErrDuplicate].
+//
+// The error message is [ErrDuplicate] followed by the duplicates, separated
+// by a semicolon and a space.
+type DuplicateError struct {
+	// Duplicates are the skipped objects, in the order they were added.
+	Duplicates []Duplicate
+}
+
+func (e *DuplicateError) Error() string {
+	msgs := make([]string, 0, len(e.Duplicates))
+	for _, d := range e.Duplicates {
+		msgs = append(msgs, d.String())
+	}
+	return ErrDuplicate.Error() + ": " + strings.Join(msgs, "; ")
+}
+
+func (e *DuplicateError) Unwrap() error {
+	return ErrDuplicate
+}
+
+// StaleFile is a generated file that differs from the one on disk.
+type StaleFile struct {
+	// Name is the name of the file.
+	Name string
+
+	// Diff is the unified diff between the file on disk and the generated
+	// one. If the file does not exist, it is compared with an empty file.
+	Diff string
+}
+
+// StaleError is the error returned by [Aliaser.Check] when some generated
+// files differ from the ones on disk. It wraps [ErrStale].
+//
+// The error message is [ErrStale] followed by the names of the stale files,
+// separated by a comma and a space.
+type StaleError struct {
+	// Files are the stale files, in the order they were checked.
+	Files []StaleFile
+}
+
+func (e *StaleError) Error() string {
+	names := make([]string, 0, len(e.Files))
+	for _, f := range e.Files {
+		names = append(names, f.Name)
+	}
+	return ErrStale.Error() + ": " + strings.Join(names, ", ")
+}
+
+func (e *StaleError) Unwrap() error {
+	return ErrStale
+}
+
+// Diff returns the concatenation of the diffs of the stale files.
+func (e *StaleError) Diff() string {
+	var b strings.Builder
+	for _, f := range e.Files {
+		b.WriteString(f.Diff)
+	}
+	return b.String()
+}
@@ -13,3 +13,16 @@ func TestPackagesErrors(t *testing.T) {
 	errs := PackagesErrors{err1, err2}
 	assert.Equal(t, err1.Error()+"; "+err2.Error(), errs.Error())
 }
+
+func TestDuplicateError(t *testing.T) {
+	err := &DuplicateError{Duplicates: []Duplicate{{Name: "A"}, {Name: "B", Target: true}}}
+	assert.ErrorIs(t, err, ErrDuplicate)
+	assert.Equal(t, "duplicate object names: A; B: declared in the target package", err.Error())
+}
+
+func TestStaleError(t *testing.T) {
+	err := &StaleError{Files: []StaleFile{{Name: "a.go", Diff: "a\n"}, {Name: "b.go", Diff: "b\n"}}}
+	assert.ErrorIs(t, err, ErrStale)
+	assert.Equal(t, "stale generated files: a.go, b.go", err.Error())
+	assert.Equal(t, "a\nb\n", err.Diff())
+}
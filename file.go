@@ -6,7 +6,11 @@
 	"fmt"
 	"io"
 	"io/fs"
+	"math/rand/v2"
 	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
 )
 
 // File is an interface that extends [fs.File] with the [io.WriteSeeker] and
@@ -26,11 +30,17 @@ type File interface {
 //
 // NOTE: This function does not create the parent directories of the file.
 func OpenFileWithReset(name string) (File, func() error, error) {
+	return openFileWithReset(name, 0o644)
+}
+
+// openFileWithReset is like [OpenFileWithReset], but creates the new files
+// with the given permissions, before the umask.
+func openFileWithReset(name string, perm fs.FileMode) (File, func() error, error) {
 	exists, err := fileExists(name)
 	if err != nil {
 		return nil, nil, fmt.Errorf("exists: %w", err)
 	}
-	f, err := openFile(name)
+	f, err := openFile(name, perm)
 	if err != nil {
 		return nil, nil, fmt.Errorf("open: %w", err)
 	}
@@ -55,10 +65,122 @@ func OpenFileWithReset(name string) (File, func() error, error) {
 	}, nil
 }
 
+// WriteFileAtomic writes the file with the given name atomically: it calls
+// the given function with a temporary file created in the same directory,
+// syncs it to the disk and renames it over the destination, syncing the
+// directory as well. In this way, the destination is never left truncated or
+// partially written, even if the process is killed, and concurrent writers do
+// not interleave, since the last rename wins. If the destination already
+// exists, its permissions are kept, otherwise the file is created with mode
+// 0644, before the umask, as by [os.WriteFile]. If the destination is a
+// symbolic link, the file it points to is replaced, keeping the link.
+//
+// If any of the operations fails, the temporary file is removed and the
+// destination is left untouched.
+//
+// NOTE: This function does not create the parent directories of the file.
+func WriteFileAtomic(name string, write func(w io.Writer) error) error {
+	return writeFileAtomic(name, 0o644, write)
+}
+
+// writeFileAtomic is like [WriteFileAtomic], but creates the new files with
+// the given permissions, before the umask.
+func writeFileAtomic(name string, perm fs.FileMode, write func(w io.Writer) error) (err error) {
+	if resolved, err := filepath.EvalSymlinks(name); err == nil {
+		name = resolved
+	}
+	var keep bool
+	if fi, err := os.Stat(name); err == nil {
+		perm, keep = fi.Mode().Perm(), true
+	}
+	dir := filepath.Dir(name)
+	f, err := createTemp(dir, filepath.Base(name), perm)
+	if err != nil {
+		return fmt.Errorf("create temporary file: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			f.Close()
+			os.Remove(f.Name())
+		}
+	}()
+	if err := write(f); err != nil {
+		return err
+	}
+	if keep {
+		if err := f.Chmod(perm); err != nil {
+			return fmt.Errorf("chmod: %w", err)
+		}
+	}
+	if err := f.Sync(); err != nil {
+		return fmt.Errorf("sync: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("close: %w", err)
+	}
+	if err := os.Rename(f.Name(), name); err != nil {
+		return fmt.Errorf("rename: %w", err)
+	}
+	if err := syncDir(dir); err != nil {
+		return fmt.Errorf("sync directory: %w", err)
+	}
+	return nil
+}
+
+// createTemp creates a new temporary file in the given directory, named after
+// the given base name, as [os.CreateTemp] does, but with the given
+// permissions, so that the umask is applied to them.
+func createTemp(dir, base string, perm fs.FileMode) (*os.File, error) {
+	for i := 0; i < 10000; i++ {
+		name := filepath.Join(dir, "."+base+"."+strconv.FormatUint(uint64(rand.Uint32()), 10)+".tmp")
+		f, err := os.OpenFile(name, os.O_RDWR|os.O_CREATE|os.O_EXCL, perm)
+		if errors.Is(err, fs.ErrExist) {
+			continue
+		}
+		return f, err
+	}
+	return nil, &fs.PathError{Op: "createtemp", Path: filepath.Join(dir, "."+base+".*.tmp"), Err: fs.ErrExist}
+}
+
+// syncDir syncs the directory with the given name, so that a rename in it is
+// persisted. It does nothing on Windows, where the directories cannot be
+// synced.
+func syncDir(name string) error {
+	if runtime.GOOS == "windows" {
+		return nil
+	}
+	d, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+	return d.Sync()
+}
+
+// writeFileInPlace writes the file with the given name calling the given
+// function with the file opened by [OpenFileWithReset], resetting it to the
+// original state if the function fails. If the file does not exist, it is
+// created with the given permissions, before the umask.
+func writeFileInPlace(name string, perm fs.FileMode, write func(w io.Writer) error) error {
+	f, reset, err := openFileWithReset(name, perm)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if err := write(f); err != nil {
+		if rerr := reset(); rerr != nil {
+			return errors.Join(err, fmt.Errorf("reset file: %w", rerr))
+		}
+		return err
+	}
+	return nil
+}
+
 // openFile is a helper function to open a file for reading and writing, creating
-// it if it does not exist. It can be mocked in tests.
-var openFile = func(name string) (File, error) {
-	f, err := os.OpenFile(name, os.O_RDWR|os.O_CREATE, 0o644)
+// it with the given permissions if it does not exist. It can be mocked in
+// tests.
+var openFile = func(name string, perm fs.FileMode) (File, error) {
+	f, err := os.OpenFile(name, os.O_RDWR|os.O_CREATE, perm)
 	if err != nil {
 		return nil, fmt.Errorf("open: %w", err)
 	}
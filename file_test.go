@@ -2,6 +2,7 @@
 
 import (
 	"fmt"
+	"io"
 	"io/fs"
 	"os"
 	"path/filepath"
@@ -18,17 +19,17 @@ type fileErrorer struct {
 	f *os.File
 }
 
-func openFileErrorer(name string) (File, error) {
-	f, err := os.OpenFile(name, os.O_RDWR|os.O_CREATE, 0o644)
+func openFileErrorer(name string, perm fs.FileMode) (File, error) {
+	f, err := os.OpenFile(name, os.O_RDWR|os.O_CREATE, perm)
 	if err != nil {
 		return nil, fmt.Errorf("open: %w", err)
 	}
 	return &fileErrorer{f: f}, nil
 }
 
-func newOpenFileErrorer(c fileErrorerConfig) func(name string) (File, error) {
-	return func(name string) (File, error) {
-		f, err := openFileErrorer(name)
+func newOpenFileErrorer(c fileErrorerConfig) func(name string, perm fs.FileMode) (File, error) {
+	return func(name string, perm fs.FileMode) (File, error) {
+		f, err := openFileErrorer(name, perm)
 		if err != nil {
 			return nil, fmt.Errorf("open: %w", err)
 		}
@@ -192,3 +193,80 @@ func TestOpenFileWithReset(t *testing.T) {
 		})
 	})
 }
+
+func TestWriteFileAtomic(t *testing.T) {
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "alias.go")
+	write := func(s string) func(io.Writer) error {
+		return func(w io.Writer) error {
+			_, err := io.WriteString(w, s)
+			return err
+		}
+	}
+	t.Run("Create", func(t *testing.T) {
+		require.NoError(t, WriteFileAtomic(filename, write("package foo\n")))
+		data, err := os.ReadFile(filename)
+		require.NoError(t, err)
+		assert.Equal(t, "package foo\n", string(data))
+		fi, err := os.Stat(filename)
+		require.NoError(t, err)
+		// the umask is applied as by os.WriteFile
+		ref := filepath.Join(t.TempDir(), "ref.go")
+		require.NoError(t, os.WriteFile(ref, nil, 0o644))
+		rfi, err := os.Stat(ref)
+		require.NoError(t, err)
+		assert.Equal(t, rfi.Mode().Perm(), fi.Mode().Perm())
+	})
+	t.Run("Replace", func(t *testing.T) {
+		require.NoError(t, os.Chmod(filename, 0o600))
+		require.NoError(t, WriteFileAtomic(filename, write("package bar\n")))
+		data, err := os.ReadFile(filename)
+		require.NoError(t, err)
+		assert.Equal(t, "package bar\n", string(data))
+		fi, err := os.Stat(filename)
+		require.NoError(t, err)
+		assert.Equal(t, fs.FileMode(0o600), fi.Mode().Perm(), "mode kept")
+	})
+	t.Run("Symlink", func(t *testing.T) {
+		dir := t.TempDir()
+		target := filepath.Join(dir, "target.go")
+		require.NoError(t, os.WriteFile(target, []byte("package foo\n"), 0o600))
+		link := filepath.Join(dir, "link.go")
+		if err := os.Symlink(target, link); err != nil {
+			t.Skipf("symlink: %v", err)
+		}
+		require.NoError(t, WriteFileAtomic(link, write("package bar\n")))
+		fi, err := os.Lstat(link)
+		require.NoError(t, err)
+		assert.NotZero(t, fi.Mode()&fs.ModeSymlink, "link kept")
+		data, err := os.ReadFile(target)
+		require.NoError(t, err)
+		assert.Equal(t, "package bar\n", string(data))
+		fi, err = os.Stat(target)
+		require.NoError(t, err)
+		assert.Equal(t, fs.FileMode(0o600), fi.Mode().Perm(), "mode kept")
+	})
+	t.Run("WriteError", func(t *testing.T) {
+		assert.ErrorIs(t, WriteFileAtomic(filename, func(w io.Writer) error {
+			_, _ = io.WriteString(w, "partial")
+			return assert.AnError
+		}), assert.AnError)
+		data, err := os.ReadFile(filename)
+		require.NoError(t, err)
+		assert.Equal(t, "package bar\n", string(data), "destination untouched")
+		entries, err := os.ReadDir(dir)
+		require.NoError(t, err)
+		assert.Len(t, entries, 1, "temporary file removed")
+	})
+	t.Run("CreateTempError", func(t *testing.T) {
+		assert.Error(t, WriteFileAtomic(filepath.Join(dir, "missing", "alias.go"), write("")))
+	})
+	t.Run("RenameError", func(t *testing.T) {
+		target := filepath.Join(dir, "target")
+		require.NoError(t, os.MkdirAll(filepath.Join(target, "child"), 0o755))
+		assert.Error(t, WriteFileAtomic(target, write(""))) // non-empty directory
+		entries, err := os.ReadDir(dir)
+		require.NoError(t, err)
+		assert.Len(t, entries, 2, "temporary file removed")
+	})
+}
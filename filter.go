@@ -0,0 +1,216 @@
+package aliaser
+
+import (
+	"errors"
+	"fmt"
+	"go/types"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// Kind is the kind of an object of the loaded package. It is used to limit
+// the name filters to the objects of a single kind (see [IncludeMatching] and
+// [ExcludeMatching]).
+type Kind int
+
+const (
+	// KindAny matches the objects of any kind.
+	KindAny Kind = iota
+
+	// KindConst matches the constants.
+	KindConst
+
+	// KindVar matches the variables.
+	KindVar
+
+	// KindFunc matches the functions.
+	KindFunc
+
+	// KindType matches the types.
+	KindType
+)
+
+var kindNames = [...]string{
+	KindAny:   "any",
+	KindConst: "const",
+	KindVar:   "var",
+	KindFunc:  "func",
+	KindType:  "type",
+}
+
+// String returns the name of the kind.
+func (k Kind) String() string {
+	if k < 0 || int(k) >= len(kindNames) {
+		return fmt.Sprintf("Kind(%d)", k)
+	}
+	return kindNames[k]
+}
+
+// ParseKind returns the [Kind] with the given name, as returned by
+// [Kind.String], or an error if the name is unknown.
+func ParseKind(name string) (Kind, error) {
+	for k, n := range kindNames {
+		if n == name {
+			return Kind(k), nil
+		}
+	}
+	return 0, fmt.Errorf("unknown kind: %q", name)
+}
+
+// kindOf returns the kind of the given object.
+func kindOf(o types.Object) Kind {
+	switch o.(type) {
+	case *types.Const:
+		return KindConst
+	case *types.Var:
+		return KindVar
+	case *types.Func:
+		return KindFunc
+	case *types.TypeName:
+		return KindType
+	}
+	return KindAny
+}
+
+// nameFilter matches the names of the objects of a kind against a glob or
+// regular expression pattern.
+type nameFilter struct {
+	kind  Kind
+	match func(name string) bool
+}
+
+// newNameFilter returns a new [nameFilter] matching the objects of the given
+// kind with the given pattern. A pattern enclosed in slashes is a regular
+// expression, e.g. "/^(New|Must)/", otherwise it is a glob pattern in the
+// syntax of [path.Match], e.g. "New*".
+func newNameFilter(kind Kind, pattern string) (nameFilter, error) {
+	if kind < 0 || int(kind) >= len(kindNames) {
+		return nameFilter{}, fmt.Errorf("unknown kind: %s", kind)
+	}
+	if len(pattern) > 1 && strings.HasPrefix(pattern, "/") && strings.HasSuffix(pattern, "/") {
+		re, err := regexp.Compile(pattern[1 : len(pattern)-1])
+		if err != nil {
+			return nameFilter{}, err
+		}
+		return nameFilter{kind, re.MatchString}, nil
+	}
+	if _, err := path.Match(pattern, ""); err != nil {
+		return nameFilter{}, fmt.Errorf("%w: %q", err, pattern)
+	}
+	return nameFilter{kind, func(name string) bool {
+		ok, _ := path.Match(pattern, name)
+		return ok
+	}}, nil
+}
+
+// applies reports whether the filter applies to the objects of the given
+// kind.
+func (f nameFilter) applies(kind Kind) bool {
+	return f.kind == KindAny || f.kind == kind
+}
+
+// addNameFilters adds the filters for the given patterns to the given list,
+// joining the errors of the invalid patterns to the configuration ones.
+func (c *config) addNameFilters(filters *[]nameFilter, kind Kind, patterns []string) {
+	for _, p := range patterns {
+		f, err := newNameFilter(kind, p)
+		if err != nil {
+			c.filterErr = errors.Join(c.filterErr, fmt.Errorf("%w: %w", ErrInvalidNamePattern, err))
+			continue
+		}
+		*filters = append(*filters, f)
+	}
+}
+
+// included reports whether the given object passes the name filters. If any
+// include filter applies to the kind of the object, the object must match at
+// least one of them. In any case, the object must not match any exclude
+// filter applying to its kind, since the exclusions take precedence.
+func (c *config) included(o types.Object) bool {
+	kind := kindOf(o)
+	restricted, matched := false, false
+	for _, f := range c.includes {
+		if f.applies(kind) {
+			restricted = true
+			if matched = f.match(o.Name()); matched {
+				break
+			}
+		}
+	}
+	if restricted && !matched {
+		return false
+	}
+	for _, f := range c.excludes {
+		if f.applies(kind) && f.match(o.Name()) {
+			return false
+		}
+	}
+	return true
+}
+
+// IncludeMatching restricts the aliases of the objects of the given kind, or
+// of any kind with [KindAny], to the ones whose name matches at least one of
+// the given patterns. A pattern enclosed in slashes is a regular expression,
+// otherwise it is a glob pattern in the syntax of [path.Match].
+//
+// The include filters only restrict the kinds they apply to, so that the
+// objects of the other kinds are included unless excluded. The exclusions,
+// by kind (e.g. [ExcludeTypes]), by name (see [ExcludeNames]) or by pattern
+// (see [ExcludeMatching]), always take precedence over the inclusions.
+//
+// [New] returns an error wrapping [ErrInvalidNamePattern] if any of the
+// patterns is invalid.
+//
+// Example:
+//
+//	// alias only the functions starting with "New" or "Must" and the types
+//	// ending with "Config", keeping all the constants and variables
+//	aliaser.IncludeMatching(aliaser.KindFunc, "New*", "/^Must[A-Z]/")
+//	aliaser.IncludeMatching(aliaser.KindType, "*Config")
+func IncludeMatching(kind Kind, patterns ...string) Option {
+	return option(func(c *Config) {
+		c.addNameFilters(&c.includes, kind, patterns)
+	})
+}
+
+// ExcludeMatching excludes the objects of the given kind, or of any kind with
+// [KindAny], whose name matches any of the given patterns, with the same
+// syntax as [IncludeMatching]. An excluded object is never aliased, even if
+// it matches an include filter.
+//
+// [New] returns an error wrapping [ErrInvalidNamePattern] if any of the
+// patterns is invalid.
+//
+// Example:
+//
+//	// exclude all the objects ending with "ForTesting" and the deprecated
+//	// constructors named as "NewXxxV1"
+//	aliaser.ExcludeMatching(aliaser.KindAny, "*ForTesting")
+//	aliaser.ExcludeMatching(aliaser.KindFunc, "/^New.*V1$/")
+func ExcludeMatching(kind Kind, patterns ...string) Option {
+	return option(func(c *Config) {
+		c.addNameFilters(&c.excludes, kind, patterns)
+	})
+}
+
+// WithFilter adds a predicate filter to the ones applied to the objects of
+// the loaded package: only the objects for which all the filters return true
+// are aliased. The predicate filters are applied along with the other
+// exclusions, before the transformers (see [WithTransformers]).
+//
+// Example:
+//
+//	// alias only the types with a Read method, e.g. the io.Reader ones
+//	aliaser.WithFilter(func(obj types.Object) bool {
+//		if _, ok := obj.(*types.TypeName); !ok {
+//			return false
+//		}
+//		m, _, _ := types.LookupFieldOrMethod(types.NewPointer(obj.Type()), true, obj.Pkg(), "Read")
+//		return m != nil
+//	})
+func WithFilter(keep func(obj types.Object) bool) Option {
+	return option(func(c *Config) {
+		c.filters = append(c.filters, keep)
+	})
+}
@@ -0,0 +1,88 @@
+package aliaser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKind(t *testing.T) {
+	for _, k := range []Kind{KindAny, KindConst, KindVar, KindFunc, KindType} {
+		pk, err := ParseKind(k.String())
+		require.NoError(t, err)
+		assert.Equal(t, k, pk)
+	}
+	assert.Equal(t, "Kind(10)", Kind(10).String())
+	_, err := ParseKind("unknown")
+	assert.Error(t, err)
+}
+
+func TestNameFilter(t *testing.T) {
+	for pattern, tt := range map[string]struct {
+		match, noMatch []string
+	}{
+		"New*":          {[]string{"New", "NewFoo"}, []string{"MustNew", "new"}},
+		"?et":           {[]string{"Get", "Set"}, []string{"Reset"}},
+		"/^(New|Must)/": {[]string{"NewFoo", "MustFoo"}, []string{"FooNew"}},
+		"/Config$/":     {[]string{"Config", "ServerConfig"}, []string{"Configs"}},
+		"/":             {[]string{"/"}, []string{"Foo"}}, // not a regexp
+	} {
+		f, err := newNameFilter(KindAny, pattern)
+		require.NoError(t, err, pattern)
+		for _, name := range tt.match {
+			assert.True(t, f.match(name), "%s %s", pattern, name)
+		}
+		for _, name := range tt.noMatch {
+			assert.False(t, f.match(name), "%s %s", pattern, name)
+		}
+	}
+	for _, pattern := range []string{"[", "/(/"} {
+		_, err := newNameFilter(KindAny, pattern)
+		assert.Error(t, err, pattern)
+	}
+	_, err := newNameFilter(Kind(10), "*")
+	assert.Error(t, err)
+}
+
+func TestNameFilterOptions(t *testing.T) {
+	names := func(objs []Object) []string {
+		var names []string
+		for _, o := range objs {
+			names = append(names, o.Name())
+		}
+		return names
+	}
+	t.Run("Include", AliaserTest(func(t *testing.T, a *Aliaser) {
+		assert.Equal(t, []string{"S", "T", "U"}, names(objectsOf(a.Functions())))
+		assert.NotEmpty(t, a.Constants()) // not restricted
+	}, IncludeMatching(KindFunc, "[ST]", "/^U$/")))
+	t.Run("IncludeAny", AliaserTest(func(t *testing.T, a *Aliaser) {
+		assert.Equal(t, []string{"A"}, names(objectsOf(a.Constants())))
+		assert.Empty(t, a.Functions())
+		assert.Equal(t, []string{"N", "O"}, names(objectsOf(a.Types())))
+	}, IncludeMatching(KindAny, "A", "[NO]")))
+	t.Run("Exclude", AliaserTest(func(t *testing.T, a *Aliaser) {
+		assert.NotContains(t, names(objectsOf(a.Types())), "K")
+		assert.NotContains(t, names(objectsOf(a.Types())), "L")
+		assert.Contains(t, names(objectsOf(a.Functions())), "J")
+	}, ExcludeMatching(KindType, "[J-L]")))
+	t.Run("Precedence", AliaserTest(func(t *testing.T, a *Aliaser) {
+		assert.Equal(t, []string{"T"}, names(objectsOf(a.Functions())))
+	}, IncludeMatching(KindFunc, "[ST]", "U"), ExcludeMatching(KindAny, "S"), ExcludeNames("U")))
+	t.Run("Invalid", func(t *testing.T) {
+		_, err := New(&Config{TargetPackage: TestTarget, Pattern: TestPattern}, IncludeMatching(KindFunc, "["))
+		assert.ErrorIs(t, err, ErrInvalidNamePattern)
+		_, err = New(&Config{TargetPackage: TestTarget, Pattern: TestPattern}, ExcludeMatching(KindAny, "/(/"))
+		assert.ErrorIs(t, err, ErrInvalidNamePattern)
+	})
+}
+
+// objectsOf returns the given objects as a slice of [Object].
+func objectsOf[O Object](objs []O) []Object {
+	s := make([]Object, len(objs))
+	for i, o := range objs {
+		s[i] = o
+	}
+	return s
+}
@@ -0,0 +1,145 @@
+package aliaser
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"sync"
+
+	"github.com/marcozac/go-aliaser/util/maps"
+)
+
+// WritableFS is the file system where [Aliaser.GenerateFS] writes the
+// generated files. The names are the ones given to [Aliaser.GenerateFS] or
+// derived from them, such as the platform specific files (see
+// [PlatformFileName]), so their format depends on the implementation.
+type WritableFS interface {
+	// ReadFile reads the file with the given name. If it does not exist, the
+	// returned error must wrap [fs.ErrNotExist].
+	ReadFile(name string) ([]byte, error)
+
+	// MkdirAll creates the directory with the given name, along with any
+	// necessary parent, with the given permissions. It does nothing if the
+	// directory already exists.
+	MkdirAll(name string, perm fs.FileMode) error
+
+	// WriteFile writes the given data to the file with the given name,
+	// creating it with the given permissions, if supported, if it does not
+	// exist, or replacing its content otherwise.
+	WriteFile(name string, data []byte, perm fs.FileMode) error
+}
+
+var (
+	_ WritableFS = OSFS{}
+	_ WritableFS = (*MemFS)(nil)
+)
+
+// OSFS is a [WritableFS] backed by the operating system file system. The
+// names are operating system paths, relative to Dir, if not empty, or to the
+// current directory otherwise.
+//
+// The files are written atomically (see [WriteFileAtomic]), unless InPlace
+// is true (see [InPlaceWrites]).
+//
+// Example:
+//
+//	// writes the files into the staging directory, e.g. "staging/foo/alias.go"
+//	a.GenerateFS(aliaser.OSFS{Dir: "staging"}, "foo/alias.go")
+type OSFS struct {
+	// Dir is the directory the names are relative to.
+	Dir string
+
+	// InPlace enables the legacy in-place writes of [OpenFileWithReset].
+	InPlace bool
+}
+
+// ReadFile implements [WritableFS].
+func (o OSFS) ReadFile(name string) ([]byte, error) {
+	return os.ReadFile(o.path(name))
+}
+
+// MkdirAll implements [WritableFS].
+func (o OSFS) MkdirAll(name string, perm fs.FileMode) error {
+	return os.MkdirAll(o.path(name), perm)
+}
+
+// WriteFile implements [WritableFS]. The permissions of the existing files
+// are kept, while the new ones are created with the given permissions, before
+// the umask.
+func (o OSFS) WriteFile(name string, data []byte, perm fs.FileMode) error {
+	name = o.path(name)
+	write := func(w io.Writer) error {
+		_, err := w.Write(data)
+		return err
+	}
+	if !o.InPlace {
+		return writeFileAtomic(name, perm, write)
+	}
+	return writeFileInPlace(name, perm, write)
+}
+
+func (o OSFS) path(name string) string {
+	if o.Dir == "" {
+		return name
+	}
+	return filepath.Join(o.Dir, name)
+}
+
+// MemFS is an in-memory [WritableFS], e.g. for testing or for collecting the
+// generated files before writing them elsewhere, such as into an archive. The
+// names are slash-separated paths, cleaned by [path.Clean], and the
+// directories are implicit. The zero value is an empty file system ready to
+// use.
+//
+// All the methods are safe for concurrent use.
+type MemFS struct {
+	files map[string][]byte
+	mu    sync.RWMutex
+}
+
+// ReadFile implements [WritableFS].
+func (m *MemFS) ReadFile(name string) ([]byte, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	data, ok := m.files[path.Clean(name)]
+	if !ok {
+		return nil, &fs.PathError{Op: "read", Path: name, Err: fs.ErrNotExist}
+	}
+	return bytes.Clone(data), nil
+}
+
+// MkdirAll implements [WritableFS]. Since the directories are implicit, it
+// only checks that the name is not the one of a file.
+func (m *MemFS) MkdirAll(name string, perm fs.FileMode) error {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if _, ok := m.files[path.Clean(name)]; ok {
+		return &fs.PathError{Op: "mkdir", Path: name, Err: errors.New("not a directory")}
+	}
+	return nil
+}
+
+// WriteFile implements [WritableFS]. The permissions are ignored.
+func (m *MemFS) WriteFile(name string, data []byte, perm fs.FileMode) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.files == nil {
+		m.files = make(map[string][]byte)
+	}
+	m.files[path.Clean(name)] = bytes.Clone(data)
+	return nil
+}
+
+// Names returns the sorted names of the files.
+func (m *MemFS) Names() []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	names := maps.Keys(m.files)
+	slices.Sort(names)
+	return names
+}
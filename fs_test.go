@@ -0,0 +1,79 @@
+package aliaser
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOSFS(t *testing.T) {
+	dir := t.TempDir()
+	for _, o := range []OSFS{{Dir: dir}, {Dir: dir, InPlace: true}} {
+		name := "foo/alias.go"
+		if o.InPlace {
+			name = "bar/alias.go"
+		}
+		_, err := o.ReadFile(name)
+		assert.ErrorIs(t, err, fs.ErrNotExist)
+		require.NoError(t, o.MkdirAll(filepath.Dir(name), 0o755))
+		require.NoError(t, o.WriteFile(name, []byte("package foo\n"), 0o600))
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		require.NoError(t, err)
+		assert.Equal(t, "package foo\n", string(data))
+		fi, err := os.Stat(filepath.Join(dir, name))
+		require.NoError(t, err)
+		assert.Equal(t, fs.FileMode(0o600), fi.Mode().Perm())
+		require.NoError(t, o.WriteFile(name, []byte("package bar\n"), 0o644))
+		data, err = o.ReadFile(name)
+		require.NoError(t, err)
+		assert.Equal(t, "package bar\n", string(data))
+		fi, err = os.Stat(filepath.Join(dir, name))
+		require.NoError(t, err)
+		assert.Equal(t, fs.FileMode(0o600), fi.Mode().Perm(), "mode kept")
+	}
+	t.Run("Umask", func(t *testing.T) {
+		// the umask is applied to the new files as by os.WriteFile
+		dir := t.TempDir()
+		ref := filepath.Join(dir, "ref.go")
+		require.NoError(t, os.WriteFile(ref, nil, 0o666))
+		rfi, err := os.Stat(ref)
+		require.NoError(t, err)
+		for _, o := range []OSFS{{Dir: dir}, {Dir: dir, InPlace: true}} {
+			name := "atomic.go"
+			if o.InPlace {
+				name = "inplace.go"
+			}
+			require.NoError(t, o.WriteFile(name, []byte("package foo\n"), 0o666))
+			fi, err := os.Stat(filepath.Join(dir, name))
+			require.NoError(t, err)
+			assert.Equal(t, rfi.Mode().Perm(), fi.Mode().Perm(), name)
+		}
+	})
+	t.Run("WriteError", func(t *testing.T) {
+		assert.Error(t, OSFS{Dir: dir}.WriteFile("missing/alias.go", nil, 0o644))
+	})
+}
+
+func TestMemFS(t *testing.T) {
+	var m MemFS
+	_, err := m.ReadFile("foo/alias.go")
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+	assert.NoError(t, m.MkdirAll("foo", 0o755))
+	data := []byte("package foo\n")
+	require.NoError(t, m.WriteFile("foo/./alias.go", data, 0o644))
+	data[0] = 'P' // not shared
+	got, err := m.ReadFile("foo/alias.go")
+	require.NoError(t, err)
+	assert.Equal(t, "package foo\n", string(got))
+	got[0] = 'P' // not shared
+	got, err = m.ReadFile("foo/alias.go")
+	require.NoError(t, err)
+	assert.Equal(t, "package foo\n", string(got))
+	assert.Error(t, m.MkdirAll("foo/alias.go", 0o755))
+	require.NoError(t, m.WriteFile("bar.go", nil, 0o644))
+	assert.Equal(t, []string{"bar.go", "foo/alias.go"}, m.Names())
+}
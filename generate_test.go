@@ -16,6 +16,9 @@ func TestGenerateFile(t *testing.T) {
 				"// Code generated by aliaser. DO NOT EDIT.",
 				"//go:build testout",
 			)),
+			ForwardMethods(true),
+			WithVarStrategy(VarPointer, "Ptr"),
+			WithVarStrategy(VarAccessors, "X"),
 		))
 		assert.FileExists(t, filename)
 	})
@@ -1,9 +1,9 @@
 package importer
 
 import (
-	"fmt"
 	"go/types"
 	"slices"
+	"strings"
 	"sync"
 	"sync/atomic"
 
@@ -21,12 +21,38 @@ type Importer struct {
 	imports        map[string]*types.Package
 	toAlias        atomic.Bool
 	aliasedImports map[string]string
-	mu             sync.RWMutex
+	specs          []Import
+	strategy       Strategy
+	// table is the map of the aliases restored by [Importer.Restore],
+	// formatted as "path:alias".
+	table map[string]string
+	// reserved is the set of the names never used as aliases (see
+	// [Importer.Reserve]).
+	reserved map[string]bool
+	frozen   bool
+	mu       sync.RWMutex
 }
 
-// New returns a new [Importer].
+// New returns a new [Importer] using the [AliasAll] strategy.
 func New() *Importer {
-	return &Importer{imports: make(map[string]*types.Package)}
+	return NewWithStrategy(AliasAll{})
+}
+
+// NewWithStrategy returns a new [Importer] using the given [Strategy] to
+// choose the names of the imported packages. If the strategy is nil,
+// [AliasAll] is used.
+//
+// The Go keywords and the predeclared identifiers are reserved by default,
+// so that a package named, for example, "any" is imported as "any_2".
+func NewWithStrategy(s Strategy) *Importer {
+	if s == nil {
+		s = AliasAll{}
+	}
+	return &Importer{
+		imports:  make(map[string]*types.Package),
+		strategy: s,
+		reserved: predeclared(),
+	}
 }
 
 // AddImport adds the given package to the list of imports ensuring that the
@@ -44,10 +70,37 @@ func (imp *Importer) addImport(p *types.Package) {
 		return
 	}
 	path := p.Path()
-	if _, ok := imp.imports[path]; !ok {
-		imp.toAlias.Store(true)
-		imp.imports[path] = p
+	if _, ok := imp.imports[path]; ok {
+		return
+	}
+	imp.imports[path] = p
+	if imp.frozen {
+		imp.addFrozenImport(p)
+		return
 	}
+	imp.toAlias.Store(true)
+}
+
+// addFrozenImport gives the given package, added after the importer has been
+// frozen, an alias that does not conflict with the existing ones.
+func (imp *Importer) addFrozenImport(p *types.Package) {
+	taken := maps.Clone(imp.reserved)
+	for _, spec := range imp.specs {
+		taken[spec.Name] = true
+	}
+	spec := imp.strategy.Imports([]*types.Package{p})[0]
+	name, ok := imp.table[p.Path()]
+	if !ok {
+		name = spec.Name
+	}
+	spec = stableImport(p, spec, uniqueName(name, taken))
+	i, _ := slices.BinarySearchFunc(imp.specs, spec.Path, func(spec Import, path string) int {
+		return strings.Compare(spec.Path, path)
+	})
+	// copy on write, since the previous results may be still in use
+	imp.specs = slices.Insert(slices.Clip(imp.specs), i, spec)
+	imp.aliasedImports = maps.Clone(imp.aliasedImports)
+	imp.aliasedImports[spec.Path] = spec.Name
 }
 
 // Imports creates a new slice containing all the imported packages.
@@ -61,18 +114,20 @@ func (imp *Importer) importsList() []*types.Package {
 	return maps.Values(imp.imports)
 }
 
-// AliasedImports returns the map of package imports formatted as "path:alias".
-// All the imports are aliased to avoid conflicts on same-name packages.
-// Moreover, the imports are sorted by path before aliasing them, ensuring
-// deterministic results and avoiding, for example, false positives in tests
-// comparing the generated code.
+// AliasedImports returns the map of package imports formatted as "path:alias",
+// where the alias is the name used to refer to the package in the generated
+// code, chosen by the [Strategy] of the importer to avoid conflicts on
+// same-name packages. Moreover, the imports are sorted by path before aliasing
+// them, ensuring deterministic results and avoiding, for example, false
+// positives in tests comparing the generated code.
 //
 // NOTE:
-// The method should be called only once, after all imports have been added, or
-// it may produce inconsistent results. For example, if a package (B) is added
-// after the first call and has the same name of another one (A) but a path
-// that sorts before, A alias will be different from the first result. See the
-// example below for more details.
+// Unless the importer is frozen (see [Importer.Freeze]), the method should be
+// called only once, after all imports have been added, or it may produce
+// inconsistent results. For example, if a package (B) is added after the first
+// call and has the same name of another one (A) but a path that sorts before,
+// A alias will be different from the first result. See the example below for
+// more details.
 //
 // Example:
 //
@@ -93,6 +148,14 @@ func (imp *Importer) importsList() []*types.Package {
 //	// "github.com/marcozac/go-aliaser/fake1": "fake"
 //	// "github.com/marcozac/go-aliaser/fake2": "fake_2"
 //	// "github.com/marcozac/go-aliaser/fake3": "fake_3" // different alias!
+//
+//	// Good!
+//	a.Freeze()
+//	a.AddImport(types.NewPackage("github.com/marcozac/go-aliaser/fake2", "fake"))
+//	imports = a.AliasedImports()
+//	// "github.com/marcozac/go-aliaser/fake1": "fake"
+//	// "github.com/marcozac/go-aliaser/fake2": "fake_3" // new alias
+//	// "github.com/marcozac/go-aliaser/fake3": "fake_2" // same alias
 func (imp *Importer) AliasedImports() map[string]string {
 	imp.mu.Lock()
 	defer imp.mu.Unlock()
@@ -102,22 +165,138 @@ func (imp *Importer) AliasedImports() map[string]string {
 	return imp.aliasedImports
 }
 
+// ImportSpecs returns the declarations of the imported packages, sorted by
+// path, as chosen by the [Strategy] of the importer. The same considerations
+// of [Importer.AliasedImports] apply.
+func (imp *Importer) ImportSpecs() []Import {
+	imp.mu.Lock()
+	defer imp.mu.Unlock()
+	if imp.toAlias.Load() {
+		imp.aliasImports()
+	}
+	return imp.specs
+}
+
 func (imp *Importer) aliasImports() {
 	paths := maps.Keys(imp.imports)
 	slices.Sort(paths)
-	aliases := make([]string, 0, len(paths))
-	imp.aliasedImports = make(map[string]string, len(paths))
-	for _, path := range paths {
-		name := imp.imports[path].Name()
-		alias := name
-		for i := 2; ; i++ {
-			if !slices.Contains(aliases, alias) {
-				aliases = append(aliases, alias)
-				imp.aliasedImports[path] = alias
-				break
-			}
-			alias = fmt.Sprintf("%s_%d", name, i)
+	pkgs := make([]*types.Package, len(paths))
+	for i, path := range paths {
+		pkgs[i] = imp.imports[path]
+	}
+	imp.specs = imp.strategy.Imports(pkgs)
+	imp.resolveImports(pkgs)
+	imp.aliasedImports = make(map[string]string, len(imp.specs))
+	for _, spec := range imp.specs {
+		imp.aliasedImports[spec.Path] = spec.Name
+	}
+	imp.toAlias.Store(false)
+}
+
+// resolveImports replaces the names chosen by the strategy with the ones
+// restored by [Importer.Restore], giving the other packages a name that does
+// not conflict with them nor with the reserved names.
+func (imp *Importer) resolveImports(pkgs []*types.Package) {
+	taken := maps.Clone(imp.reserved)
+	for i, p := range pkgs {
+		if name, ok := imp.table[p.Path()]; ok {
+			imp.specs[i] = stableImport(p, imp.specs[i], uniqueName(name, taken))
+		}
+	}
+	for i, p := range pkgs {
+		if _, ok := imp.table[p.Path()]; ok {
+			continue
 		}
+		name := imp.specs[i].Name
+		if taken[name] {
+			name = p.Name() // avoid suffixing a suffixed name, e.g. "fake_2_2"
+		}
+		imp.specs[i] = stableImport(p, imp.specs[i], uniqueName(name, taken))
+	}
+}
+
+// Freeze freezes the aliases of the imported packages. After the call, the
+// aliases returned by [Importer.AliasedImports] never change: a package added
+// later is given a new alias that does not conflict with the existing ones,
+// even if its path sorts before theirs.
+//
+// It should be called after all the packages to alias have been added and
+// before generating any code depending on the aliases, such as the wrappers
+// of the signatures (see [Importer.AliasedImports]).
+func (imp *Importer) Freeze() {
+	imp.mu.Lock()
+	defer imp.mu.Unlock()
+	if imp.toAlias.Load() {
+		imp.aliasImports()
+	}
+	if imp.aliasedImports == nil {
+		imp.aliasedImports = make(map[string]string)
+	}
+	imp.frozen = true
+}
+
+// Frozen reports whether the importer has been frozen by [Importer.Freeze].
+func (imp *Importer) Frozen() bool {
+	imp.mu.RLock()
+	defer imp.mu.RUnlock()
+	return imp.frozen
+}
+
+// Snapshot returns a copy of the map of package imports formatted as
+// "path:alias", that can be passed to [Importer.Restore] to keep the same
+// aliases in a later run, e.g. when regenerating the code.
+func (imp *Importer) Snapshot() map[string]string {
+	return maps.Clone(imp.AliasedImports())
+}
+
+// Restore restores the given map of package aliases formatted as "path:alias",
+// as returned by [Importer.Snapshot] or [ParseAliases]. The imported packages
+// with a path in the map keep the restored alias, while the others are given
+// a name, chosen by the [Strategy] of the importer, that does not conflict
+// with them. The paths not imported are ignored.
+//
+// In this way, the aliases of the generated code stay the same across the
+// regenerations, even if a new package with a conflicting name is imported.
+//
+// Example:
+//
+//	imp.Restore(map[string]string{"github.com/marcozac/go-aliaser/fake3": "fake_2"})
+//	imp.AddImport(types.NewPackage("github.com/marcozac/go-aliaser/fake1", "fake"))
+//	imp.AddImport(types.NewPackage("github.com/marcozac/go-aliaser/fake2", "fake"))
+//	imp.AddImport(types.NewPackage("github.com/marcozac/go-aliaser/fake3", "fake"))
+//	imports := imp.AliasedImports()
+//	// "github.com/marcozac/go-aliaser/fake1": "fake"
+//	// "github.com/marcozac/go-aliaser/fake2": "fake_3"
+//	// "github.com/marcozac/go-aliaser/fake3": "fake_2"
+func (imp *Importer) Restore(table map[string]string) {
+	imp.mu.Lock()
+	defer imp.mu.Unlock()
+	imp.table = maps.Clone(table)
+	if !imp.frozen {
+		imp.toAlias.Store(true)
+	}
+}
+
+// Reserve reserves the given names, so that they are never used as aliases of
+// the imported packages, e.g. the names declared in the package where the
+// code is generated. As for [Importer.Restore], the aliases of a frozen
+// importer are not changed, and the names are avoided only by the packages
+// added later.
+//
+// Example:
+//
+//	imp.Reserve("fake")
+//	imp.AddImport(types.NewPackage("github.com/marcozac/go-aliaser/fake1", "fake"))
+//	imports := imp.AliasedImports()
+//	// "github.com/marcozac/go-aliaser/fake1": "fake_2"
+func (imp *Importer) Reserve(names ...string) {
+	imp.mu.Lock()
+	defer imp.mu.Unlock()
+	for _, name := range names {
+		imp.reserved[name] = true
+	}
+	if !imp.frozen {
+		imp.toAlias.Store(true)
 	}
 }
 
@@ -84,3 +84,128 @@ func TestImporter(t *testing.T) {
 		wg.Wait()
 	})
 }
+
+func TestImporterFreeze(t *testing.T) {
+	const (
+		pkgName  = "fake"
+		pkgPath1 = "github.com/marcozac/go-aliaser/fake1"
+		pkgPath2 = "github.com/marcozac/go-aliaser/fake2"
+		pkgPath3 = "github.com/marcozac/go-aliaser/fake3"
+	)
+	t.Run("Freeze", func(t *testing.T) {
+		imp := New()
+		imp.AddImport(types.NewPackage(pkgPath1, pkgName))
+		imp.AddImport(types.NewPackage(pkgPath3, pkgName))
+		assert.False(t, imp.Frozen())
+		imp.Freeze()
+		assert.True(t, imp.Frozen())
+		imp.AddImport(types.NewPackage(pkgPath2, pkgName))
+		imp.AddImport(types.NewPackage(pkgPath2, pkgName)) // already exists
+		assert.Equal(t, map[string]string{
+			pkgPath1: pkgName,
+			pkgPath2: pkgName + "_3",
+			pkgPath3: pkgName + "_2",
+		}, imp.AliasedImports())
+		assert.Equal(t, []Import{
+			{pkgPath1, pkgName, true},
+			{pkgPath2, pkgName + "_3", true},
+			{pkgPath3, pkgName + "_2", true},
+		}, imp.ImportSpecs())
+	})
+	t.Run("FreezeEmpty", func(t *testing.T) {
+		imp := NewWithStrategy(AliasConflicts{})
+		imp.Freeze()
+		imp.AddImport(types.NewPackage("encoding/json", "json"))
+		assert.Equal(t, []Import{{"encoding/json", "json", false}}, imp.ImportSpecs())
+	})
+	t.Run("Restore", func(t *testing.T) {
+		imp := NewWithStrategy(AliasConflicts{})
+		imp.Restore(map[string]string{pkgPath3: pkgName, "unknown": "unknown"})
+		imp.AddImport(types.NewPackage(pkgPath1, pkgName))
+		imp.AddImport(types.NewPackage(pkgPath2, pkgName))
+		imp.AddImport(types.NewPackage(pkgPath3, pkgName))
+		assert.Equal(t, map[string]string{
+			pkgPath1: pkgName + "_2",
+			pkgPath2: pkgName + "_3",
+			pkgPath3: pkgName,
+		}, imp.Snapshot())
+		assert.Equal(t, []Import{
+			{pkgPath1, pkgName + "_2", true},
+			{pkgPath2, pkgName + "_3", true},
+			{pkgPath3, pkgName, true},
+		}, imp.ImportSpecs())
+	})
+	t.Run("RestoreFrozen", func(t *testing.T) {
+		imp := New()
+		imp.Freeze()
+		imp.Restore(map[string]string{pkgPath2: pkgName + "_2"})
+		imp.AddImport(types.NewPackage(pkgPath1, pkgName))
+		imp.AddImport(types.NewPackage(pkgPath2, pkgName))
+		imp.AddImport(types.NewPackage(pkgPath3, pkgName))
+		assert.Equal(t, map[string]string{
+			pkgPath1: pkgName,
+			pkgPath2: pkgName + "_2",
+			pkgPath3: pkgName + "_3",
+		}, imp.Snapshot())
+	})
+}
+
+func TestParseAliases(t *testing.T) {
+	aliases, err := ParseAliases("alias.go", `package foo
+
+import (
+	"encoding/json"
+	json_2 "example.com/json"
+	"gopkg.in/yaml.v3"
+	_ "embed"
+	. "strings"
+)
+`)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"encoding/json":    "json",
+		"example.com/json": "json_2",
+		"gopkg.in/yaml.v3": "yaml",
+	}, aliases)
+	_, err = ParseAliases("alias.go", "package")
+	assert.Error(t, err)
+}
+
+func TestImporterReserve(t *testing.T) {
+	t.Run("Predeclared", func(t *testing.T) {
+		imp := NewWithStrategy(AliasConflicts{})
+		imp.AddImport(types.NewPackage("example.com/any", "any"))
+		imp.AddImport(types.NewPackage("example.com/go", "go"))
+		imp.AddImport(types.NewPackage("example.com/len", "len"))
+		imp.AddImport(types.NewPackage("example.com/string", "string"))
+		imp.AddImport(types.NewPackage("errors", "errors"))
+		assert.Equal(t, []Import{
+			{"errors", "errors", false},
+			{"example.com/any", "any_2", true},
+			{"example.com/go", "go_2", true},
+			{"example.com/len", "len_2", true},
+			{"example.com/string", "string_2", true},
+		}, imp.ImportSpecs())
+	})
+	t.Run("Reserve", func(t *testing.T) {
+		imp := New()
+		imp.AddImport(types.NewPackage("encoding/json", "json"))
+		imp.AddImport(types.NewPackage("example.com/json", "json"))
+		imp.Reserve("json", "json_3")
+		assert.Equal(t, map[string]string{
+			"encoding/json":    "json_2",
+			"example.com/json": "json_4",
+		}, imp.AliasedImports())
+	})
+	t.Run("Frozen", func(t *testing.T) {
+		imp := New()
+		imp.AddImport(types.NewPackage("encoding/json", "json"))
+		imp.Freeze()
+		imp.Reserve("json", "yaml")
+		imp.AddImport(types.NewPackage("gopkg.in/yaml.v3", "yaml"))
+		assert.Equal(t, map[string]string{
+			"encoding/json":    "json",
+			"gopkg.in/yaml.v3": "yaml_2",
+		}, imp.AliasedImports())
+	})
+}
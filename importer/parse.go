@@ -0,0 +1,48 @@
+package importer
+
+import (
+	"fmt"
+	"go/parser"
+	"go/token"
+	"strconv"
+)
+
+// ParseAliases parses the imports of the Go source file with the given name
+// and returns the map of the package aliases formatted as "path:alias", that
+// can be passed to [Importer.Restore]. As in [parser.ParseFile], if src is
+// not nil, it is used as the source of the file instead of reading it.
+//
+// The name of the imports without an explicit one is the name assumed by the
+// go tools for the import path, as in the code generated by the strategies of
+// this package. The blank and the dot imports are ignored.
+//
+// Example:
+//
+//	// import (
+//	//	"encoding/json"
+//	//	json_2 "example.com/json"
+//	// )
+//	aliases, err := importer.ParseAliases("alias.go", nil)
+//	// "encoding/json": "json"
+//	// "example.com/json": "json_2"
+func ParseAliases(filename string, src any) (map[string]string, error) {
+	f, err := parser.ParseFile(token.NewFileSet(), filename, src, parser.ImportsOnly|parser.SkipObjectResolution)
+	if err != nil {
+		return nil, fmt.Errorf("parse: %w", err)
+	}
+	aliases := make(map[string]string, len(f.Imports))
+	for _, spec := range f.Imports {
+		path, err := strconv.Unquote(spec.Path.Value)
+		if err != nil {
+			return nil, fmt.Errorf("import path: %w", err)
+		}
+		name := assumedName(path)
+		if spec.Name != nil {
+			name = spec.Name.Name
+		}
+		if name != "_" && name != "." {
+			aliases[path] = name
+		}
+	}
+	return aliases, nil
+}
@@ -0,0 +1,28 @@
+package importer
+
+import "go/types"
+
+// keywords is the list of the Go keywords.
+var keywords = []string{
+	"break", "case", "chan", "const", "continue", "default", "defer", "else",
+	"fallthrough", "for", "func", "go", "goto", "if", "import", "interface",
+	"map", "package", "range", "return", "select", "struct", "switch", "type",
+	"var",
+}
+
+// predeclared returns the set of the names reserved by default: the Go
+// keywords, the predeclared identifiers of the universe scope, e.g. "any" or
+// "len", and "init", that cannot be declared at package scope other than as
+// a function.
+func predeclared() map[string]bool {
+	names := types.Universe.Names()
+	reserved := make(map[string]bool, len(keywords)+len(names)+1)
+	for _, name := range keywords {
+		reserved[name] = true
+	}
+	for _, name := range names {
+		reserved[name] = true
+	}
+	reserved["init"] = true
+	return reserved
+}
@@ -0,0 +1,168 @@
+package importer
+
+import (
+	"fmt"
+	"go/types"
+	"path"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Import is the declaration of an imported package in the generated code.
+type Import struct {
+	// Path is the import path of the package.
+	Path string
+
+	// Name is the name used to refer to the package in the generated code.
+	Name string
+
+	// Explicit reports whether the name must be declared in the import spec,
+	// e.g. `json_2 "example.com/json"`, or it can be omitted since it is the
+	// name assumed by the go tools for the import path.
+	Explicit bool
+}
+
+// Strategy is the strategy used to choose the names of the imported packages.
+type Strategy interface {
+	// Imports returns the declarations of the given packages, sorted by path,
+	// in the same order. The names of the returned imports must be unique.
+	Imports(pkgs []*types.Package) []Import
+}
+
+// AliasAll is the default [Strategy]. It declares an explicit name for every
+// import, even if it matches the package name. If several packages have the
+// same name, the first one by path keeps it and the others are suffixed with
+// an increasing number.
+//
+// Example:
+//
+//	import (
+//		bytes "bytes"
+//		json "encoding/json"
+//		json_2 "example.com/json"
+//	)
+type AliasAll struct{}
+
+// Imports implements [Strategy].
+func (AliasAll) Imports(pkgs []*types.Package) []Import {
+	taken := make(map[string]bool, len(pkgs))
+	imports := make([]Import, len(pkgs))
+	for i, p := range pkgs {
+		imports[i] = Import{p.Path(), uniqueName(p.Name(), taken), true}
+	}
+	return imports
+}
+
+// AliasConflicts is a [Strategy] that declares an explicit name only when
+// needed, that is when several packages have the same name, resolved as
+// with [AliasAll], or when the package name is not the one assumed for its
+// import path, matching the output of goimports.
+//
+// Example:
+//
+//	import (
+//		"bytes"
+//		"encoding/json"
+//		json_2 "example.com/json"
+//		example "example.com/lib"
+//	)
+type AliasConflicts struct{}
+
+// Imports implements [Strategy].
+func (AliasConflicts) Imports(pkgs []*types.Package) []Import {
+	taken := make(map[string]bool, len(pkgs))
+	imports := make([]Import, len(pkgs))
+	for i, p := range pkgs {
+		imports[i] = newImport(p, uniqueName(p.Name(), taken))
+	}
+	return imports
+}
+
+// AliasPinned is a [Strategy] that uses the given names for the packages with
+// the mapped import paths, e.g. "stdjson" for "encoding/json". The names of
+// the other packages are chosen as with [AliasConflicts], avoiding the pinned
+// ones. If several paths are pinned to the same name, the first one by path
+// keeps it and the others are suffixed with an increasing number.
+//
+// Example:
+//
+//	// AliasPinned{"encoding/json": "stdjson"}
+//	import (
+//		"bytes"
+//		stdjson "encoding/json"
+//		"example.com/json"
+//	)
+type AliasPinned map[string]string
+
+// Imports implements [Strategy].
+func (ap AliasPinned) Imports(pkgs []*types.Package) []Import {
+	taken := make(map[string]bool, len(pkgs))
+	imports := make([]Import, len(pkgs))
+	for i, p := range pkgs {
+		if name, ok := ap[p.Path()]; ok {
+			imports[i] = newImport(p, uniqueName(name, taken))
+		}
+	}
+	for i, p := range pkgs {
+		if _, ok := ap[p.Path()]; !ok {
+			imports[i] = newImport(p, uniqueName(p.Name(), taken))
+		}
+	}
+	return imports
+}
+
+// newImport returns the [Import] of the given package with the given name,
+// explicit only if it differs from the name assumed for its path.
+func newImport(p *types.Package, name string) Import {
+	return Import{p.Path(), name, name != p.Name() || p.Name() != assumedName(p.Path())}
+}
+
+// stableImport returns the given import of the given package with the given
+// name. If the name differs from the one chosen by the strategy, it is
+// explicit if the chosen one was or if it is not the name assumed for the
+// package path.
+func stableImport(p *types.Package, spec Import, name string) Import {
+	if name == spec.Name {
+		return spec
+	}
+	return Import{p.Path(), name, spec.Explicit || name != p.Name() || p.Name() != assumedName(p.Path())}
+}
+
+// uniqueName returns the given name, suffixed with an increasing number if it
+// is already taken, marking the result as taken.
+func uniqueName(name string, taken map[string]bool) string {
+	alias := name
+	for i := 2; taken[alias]; i++ {
+		alias = fmt.Sprintf("%s_%d", name, i)
+	}
+	taken[alias] = true
+	return alias
+}
+
+// assumedName returns the package name assumed by the go tools for the given
+// import path: the last element, skipping the major version suffix, without
+// the "go-" prefix and truncated at the first character not allowed in an
+// identifier.
+//
+// Example:
+//
+//	assumedName("github.com/example/go-yaml/v2") // "yaml"
+//	assumedName("gopkg.in/yaml.v3")               // "yaml"
+func assumedName(importPath string) string {
+	base := path.Base(importPath)
+	if strings.HasPrefix(base, "v") {
+		if _, err := strconv.Atoi(base[1:]); err == nil {
+			if dir := path.Dir(importPath); dir != "." {
+				base = path.Base(dir)
+			}
+		}
+	}
+	base = strings.TrimPrefix(base, "go-")
+	if i := strings.IndexFunc(base, func(r rune) bool {
+		return r != '_' && !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}); i >= 0 {
+		base = base[:i]
+	}
+	return base
+}
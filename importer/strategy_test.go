@@ -0,0 +1,82 @@
+package importer
+
+import (
+	"go/types"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStrategy(t *testing.T) {
+	pkgs := []*types.Package{
+		types.NewPackage("bytes", "bytes"),
+		types.NewPackage("encoding/json", "json"),
+		types.NewPackage("example.com/json", "json"),
+		types.NewPackage("example.com/lib", "example"),
+		types.NewPackage("gopkg.in/yaml.v3", "yaml"),
+	}
+	t.Run("AliasAll", func(t *testing.T) {
+		assert.Equal(t, []Import{
+			{"bytes", "bytes", true},
+			{"encoding/json", "json", true},
+			{"example.com/json", "json_2", true},
+			{"example.com/lib", "example", true},
+			{"gopkg.in/yaml.v3", "yaml", true},
+		}, AliasAll{}.Imports(pkgs))
+	})
+	t.Run("AliasConflicts", func(t *testing.T) {
+		assert.Equal(t, []Import{
+			{"bytes", "bytes", false},
+			{"encoding/json", "json", false},
+			{"example.com/json", "json_2", true},
+			{"example.com/lib", "example", true},
+			{"gopkg.in/yaml.v3", "yaml", false},
+		}, AliasConflicts{}.Imports(pkgs))
+	})
+	t.Run("AliasPinned", func(t *testing.T) {
+		assert.Equal(t, []Import{
+			{"bytes", "bytes", false},
+			{"encoding/json", "stdjson", true},
+			{"example.com/json", "json", false},
+			{"example.com/lib", "example", true},
+			{"gopkg.in/yaml.v3", "yaml", false},
+		}, AliasPinned{"encoding/json": "stdjson"}.Imports(pkgs))
+		assert.Equal(t, []Import{
+			{"bytes", "bytes", false},
+			{"encoding/json", "json_2", true},
+			{"example.com/json", "json", false},
+			{"example.com/lib", "example", true},
+			{"gopkg.in/yaml.v3", "yaml", false},
+		}, AliasPinned{"example.com/json": "json"}.Imports(pkgs), "pinned names take precedence")
+		assert.Equal(t, []Import{
+			{"bytes", "json", true},
+			{"encoding/json", "json_2", true},
+			{"example.com/json", "json_3", true},
+			{"example.com/lib", "example", true},
+			{"gopkg.in/yaml.v3", "yaml", false},
+		}, AliasPinned{"bytes": "json"}.Imports(pkgs), "other names avoid the pinned ones")
+	})
+	t.Run("ImportSpecs", func(t *testing.T) {
+		imp := NewWithStrategy(nil)
+		for _, p := range pkgs {
+			imp.AddImport(p)
+		}
+		assert.Equal(t, AliasAll{}.Imports(pkgs), imp.ImportSpecs())
+		assert.Equal(t, "json_2", imp.AliasOf(pkgs[2]))
+	})
+}
+
+func TestAssumedName(t *testing.T) {
+	for path, name := range map[string]string{
+		"bytes":                          "bytes",
+		"encoding/json":                  "json",
+		"github.com/example/go-yaml/v2":  "yaml",
+		"gopkg.in/yaml.v3":               "yaml",
+		"example.com/foo-bar":            "foo",
+		"v2":                             "v2",
+		"github.com/example/pkg/v2/sub":  "sub",
+		"github.com/example/pkg_name/v3": "pkg_name",
+	} {
+		assert.Equal(t, name, assumedName(path), path)
+	}
+}
@@ -0,0 +1,15 @@
+// This package is used to test the merging of several packages into one.
+package api
+
+// Version is the API version.
+const Version = "v1"
+
+// Error is an API error.
+type Error struct {
+	Code int
+}
+
+// NewError returns a new [Error] with the given code.
+func NewError(code int) *Error {
+	return &Error{Code: code}
+}
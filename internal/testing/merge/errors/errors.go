@@ -0,0 +1,14 @@
+// This package is used to test the merging of several packages into one.
+package errors
+
+import "github.com/marcozac/go-aliaser/internal/testing/merge/api"
+
+// Error is an error wrapping an [api.Error].
+type Error struct {
+	API *api.Error
+}
+
+// Wrap returns a new [Error] wrapping the given API error.
+func Wrap(err *api.Error) *Error {
+	return &Error{API: err}
+}
@@ -16,6 +16,7 @@
 )
 
 const (
+	// A is an exported constant.
 	A = pkg.A
 	H = pkg.H
 )
@@ -23,8 +24,35 @@
 var (
 	B = pkg.B
 	I = pkg.I
+	// Ptr is a variable aliased by pointer.
+	Ptr = &pkg.Ptr
+	// Y is a deprecated variable.
+	//
+	// Deprecated: use [X] instead.
+	Y = pkg.Y
 )
 
+// X is a mutable variable.
+func X() []string {
+	return pkg.X
+}
+
+// SetX sets the value of [pkg.X].
+func SetX(v []string) {
+	pkg.X = v
+}
+
+// Blank has blank parameters.
+func Blank(p0 context.Context, p1_ int, p1 string) int {
+	return pkg.Blank(p0, p1_, p1)
+}
+
+// C does nothing. See [D], [*E], [K.Baz], [N], [json.Foo], [stdjson] and
+// [stdjson.Marshaler].
+//
+// Multiline doc comment with a code block:
+//
+//	C()
 func C() {
 	pkg.C()
 }
@@ -37,7 +65,7 @@ func J(p1 int, p2 *string, p3 []string, p4 []*bool, p5 [2]string, p6 map[string]
 	Bar() bytes.Buffer
 	Foo() string
 	pkg.L
-}, p12 pkg.D, p13 *pkg.E, p14 []int, p15 [2]pkg.D, p16 map[pkg.D]pkg.E, p17 context.CancelCauseFunc, p18 json.Marshaler, json_ json_2.Foo, variadic ...*packages.Module) (int, any, *pkg.D, error) {
+}, p12 pkg.D, p13 *pkg.E, p14 []pkg.F, p15 [2]pkg.G, p16 map[pkg.D]pkg.E, p17 context.CancelCauseFunc, p18 json.Marshaler, json_ json_2.Foo, variadic ...*packages.Module) (int, any, *pkg.D, error) {
 	return pkg.J(p1, p2, p3, p4, p5, p6, p7, p8, p9, p10, p11, p12, p13, p14, p15, p16, p17, p18, json_, variadic...)
 }
 
@@ -45,8 +73,14 @@ func S[T any](t T) {
 	pkg.S[T](t)
 }
 
-func T[C context.Context, S ~string, T any](ctx C, s S, t T) (S, *pkg.P[T, S]) {
-	return pkg.T[C, S, T](ctx, s, t)
+// Shadow has type parameters and parameters whose names conflict with the
+// package aliases and with each other once renamed.
+func Shadow[pkg_ any, json_ ~string](context_ pkg_, pkg__ json_, json__ ...pkg_) (pkg_, json_) {
+	return pkg.Shadow[pkg_, json_](context_, pkg__, json__...)
+}
+
+func T[C context.Context, S ~string, T_ any](ctx C, s S, t T_) (S, *pkg.P[T_, S]) {
+	return pkg.T[C, S, T_](ctx, s, t)
 }
 
 func U[T any]() T {
@@ -61,14 +95,22 @@ func W() pkg.P[int, string] {
 	return pkg.W()
 }
 
+// Z is a deprecated function.
+//
+// Deprecated: use [C] instead.
+func Z() {
+	pkg.Z()
+}
+
 type (
+	// D is a string.
 	D = pkg.D
 
 	E = pkg.E
 
-	F = int
+	F = pkg.F
 
-	G = pkg.D
+	G = pkg.G
 
 	K = pkg.K
 
@@ -82,7 +124,46 @@ func W() pkg.P[int, string] {
 
 	P[T any, V ~string] pkg.P[T, V]
 
-	Q = pkg.P[string, pkg.D]
+	Q = pkg.Q
 
 	R[T json.Decoder] pkg.R[T]
+
+	// Shadowed has a type parameter whose name conflicts with a package alias.
+	Shadowed[pkg_ any] pkg.Shadowed[pkg_]
 )
+
+// Foo does nothing.
+func (r *P[T, V]) Foo() {
+	(*pkg.P[T, V])(r).Foo()
+}
+
+func (r P[T, V]) Bar(v V) (T, V) {
+	return pkg.P[T, V](r).Bar(v)
+}
+
+func (r *P[T, V]) Baz(other P[T, V], pkg_ ...string) *P[T, V] {
+	r0 := (*pkg.P[T, V])(r).Baz(pkg.P[T, V](other), pkg_...)
+	return (*P[T, V])(r0)
+}
+
+func (r P[T, V]) Qux(p0 T, p1 V) {
+	pkg.P[T, V](r).Qux(p0, p1)
+}
+
+// Old does nothing.
+//
+// Deprecated: use [P.Foo] instead.
+func (r *P[T, V]) Old() {
+	(*pkg.P[T, V])(r).Old()
+}
+
+// Get returns the value.
+func (r Shadowed[pkg_]) Get(json_ pkg_) pkg_ {
+	return pkg.Shadowed[pkg_](r).Get(json_)
+}
+
+// Set sets the value.
+func (r *Shadowed[pkg_]) Set(Shadowed_ pkg_) *Shadowed[pkg_] {
+	r0 := (*pkg.Shadowed[pkg_])(r).Set(Shadowed_)
+	return (*Shadowed[pkg_])(r0)
+}
@@ -3,6 +3,7 @@
 package out
 
 import (
+	"context"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -20,4 +21,37 @@ func TestOut(t *testing.T) {
 	assert.Equal(t, pkg.G(""), G(""))
 	assert.Equal(t, pkg.H, H)
 	assert.Equal(t, pkg.I, I)
+	t.Run("VarStrategy", func(t *testing.T) {
+		assert.Equal(t, &pkg.Ptr, Ptr)
+		p := pkg.Ptr
+		defer func() { pkg.Ptr = p }()
+		*Ptr = "p"
+		assert.Equal(t, "p", pkg.Ptr)
+		assert.Equal(t, pkg.X, X())
+		x := pkg.X
+		defer SetX(x)
+		SetX([]string{"y"})
+		assert.Equal(t, []string{"y"}, pkg.X)
+	})
+	t.Run("ForwardMethods", func(t *testing.T) {
+		p := &P[int, string]{V: "v"}
+		assert.NotPanics(t, p.Foo)
+		v0, v1 := P[int, string]{N: pkg.N[int]{Foo: 1}}.Bar("bar")
+		assert.Equal(t, 1, v0)
+		assert.Equal(t, "bar", v1)
+		assert.Equal(t, p, p.Baz(P[int, string]{V: "baz"}))
+		assert.Equal(t, "baz", p.V)
+		assert.NotPanics(t, func() { P[int, string]{}.Qux(1, "qux") })
+	})
+	t.Run("Shadowing", func(t *testing.T) {
+		v0, v1 := Shadow(1, "a")
+		assert.Equal(t, 1, v0)
+		assert.Equal(t, "a", v1)
+		s := &Shadowed[int]{}
+		assert.Equal(t, s, s.Set(2))
+		assert.Equal(t, 2, s.Get(0))
+	})
+	t.Run("BlankParams", func(t *testing.T) {
+		assert.Equal(t, 0, Blank(context.Background(), 1, "blank"))
+	})
 }
@@ -14,6 +14,7 @@
 )
 
 const (
+	// A is an exported constant.
 	A = 1
 	a = 1
 )
@@ -23,9 +24,16 @@
 	b = "b"
 )
 
+// C does nothing. See [D], [*E], [K.Baz], [N], [json.Foo], [stdjson] and
+// [stdjson.Marshaler].
+//
+// Multiline doc comment with a code block:
+//
+//	C()
 func C() {}
 
 type (
+	// D is a string.
 	D string
 	E any
 	F = int
@@ -92,8 +100,25 @@ type P[T any, V ~string] struct {
 	V V
 }
 
+// Foo does nothing.
 func (*P[T, V]) Foo() {}
 
+func (p P[T, V]) Bar(v V) (T, V) {
+	return p.N.Foo, v
+}
+
+func (p *P[T, V]) Baz(other P[T, V], pkg ...string) *P[T, V] {
+	p.V = other.V
+	return p
+}
+
+func (P[T, V]) Qux(T, V) {}
+
+// Old does nothing.
+//
+// Deprecated: use [P.Foo] instead.
+func (*P[T, V]) Old() {}
+
 type Q = P[string, D]
 
 type R[T stdjson.Decoder] P[T, string]
@@ -116,3 +141,46 @@ func V[I ~string]() P[int, I] {
 func W() P[int, string] {
 	return P[int, string]{}
 }
+
+// X is a mutable variable.
+var X = []string{"x"}
+
+// Ptr is a variable aliased by pointer.
+var Ptr = "ptr"
+
+// Y is a deprecated variable.
+//
+// Deprecated: use [X] instead.
+var Y = 0
+
+// Z is a deprecated function.
+//
+// Deprecated: use [C] instead.
+func Z() {}
+
+// Blank has blank parameters.
+func Blank(_ context.Context, _ int, p1 string) int {
+	return 0
+}
+
+// Shadow has type parameters and parameters whose names conflict with the
+// package aliases and with each other once renamed.
+func Shadow[pkg any, json ~string](context pkg, pkg_ json, json_ ...pkg) (pkg, json) {
+	return context, pkg_
+}
+
+// Shadowed has a type parameter whose name conflicts with a package alias.
+type Shadowed[pkg any] struct {
+	V pkg
+}
+
+// Get returns the value.
+func (s Shadowed[pkg]) Get(json pkg) pkg {
+	return s.V
+}
+
+// Set sets the value.
+func (s *Shadowed[pkg]) Set(Shadowed pkg) *Shadowed[pkg] {
+	s.V = Shadowed
+	return s
+}
@@ -0,0 +1,9 @@
+// Package platform declares objects on some platforms only, for testing the
+// platform-aware generation.
+package platform
+
+// Shared is declared on all the platforms.
+const Shared = 1
+
+// Common is declared on all the platforms.
+func Common(s string) string { return s }
@@ -0,0 +1,10 @@
+package platform
+
+// Darwin is declared only on darwin.
+func Darwin() {}
+
+// Sys has a different signature on each platform.
+func Sys(fd uintptr) error { return nil }
+
+// Unix is declared on linux and darwin.
+func Unix() {}
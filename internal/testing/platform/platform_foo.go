@@ -0,0 +1,6 @@
+//go:build foo
+
+package platform
+
+// Foo is declared only with the foo build tag.
+func Foo() {}
@@ -0,0 +1,10 @@
+package platform
+
+// Linux is declared only on linux.
+func Linux() {}
+
+// Sys has a different signature on each platform.
+func Sys(fd int) error { return nil }
+
+// Unix is declared on linux and darwin.
+func Unix() {}
@@ -0,0 +1,4 @@
+package platform
+
+// Sys has a different signature on each platform.
+func Sys(handle uintptr, name string) error { return nil }
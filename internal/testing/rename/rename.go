@@ -0,0 +1,30 @@
+// Package rename declares objects whose aliases are renamed, for testing the
+// renaming rules.
+package rename
+
+// Version is the client version.
+const Version = "v1"
+
+// Client is a client.
+type Client struct{ Name string }
+
+// Options are the options of a [Client].
+type Options[T any] struct{ Value T }
+
+// DefaultClient is the default client.
+var DefaultClient = &Client{}
+
+// NewClient returns a new client.
+func NewClient() *Client { return &Client{} }
+
+// NewClientWithOptions returns a new client with the given options.
+func NewClientWithOptions(opts Options[*Client]) *Client { return opts.Value }
+
+// Clients returns the given clients by name.
+func Clients(cs ...*Client) map[string]*Client {
+	m := make(map[string]*Client, len(cs))
+	for _, c := range cs {
+		m[c.Name] = c
+	}
+	return m
+}
@@ -0,0 +1,9 @@
+// Package setter declares a variable and a function with the name of its
+// setter, for testing the duplicate names of the accessors.
+package setter
+
+// Foo is a variable.
+var Foo int
+
+// SetFoo sets the value of [Foo].
+func SetFoo(v int) { Foo = v }
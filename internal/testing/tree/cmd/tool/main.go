@@ -0,0 +1,4 @@
+// This package is used to test that the main packages are not mirrored.
+package main
+
+func main() {}
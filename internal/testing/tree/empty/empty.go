@@ -0,0 +1,5 @@
+// This package is used to test that the packages without exported objects
+// are not mirrored.
+package empty
+
+var empty = "empty"
@@ -0,0 +1,8 @@
+// This package is used to test the mirroring of a package whose directory
+// name is not a valid package name.
+package sub
+
+// Sub is declared in a subpackage of the tree.
+func Sub() string {
+	return "sub"
+}
@@ -0,0 +1,5 @@
+// This package is used to test that the internal packages are not mirrored.
+package hidden
+
+// Hidden is declared in an internal package.
+var Hidden = "hidden"
@@ -0,0 +1,5 @@
+// This package is used to test the mirroring of a package tree.
+package tree
+
+// Root is declared in the root package of the tree.
+const Root = "root"
@@ -0,0 +1,180 @@
+package aliaser
+
+import (
+	"fmt"
+	"go/build"
+	"go/token"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"unicode"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// Mirror loads the packages matched by the given pattern, e.g.
+// "github.com/example/lib/...", and generates an alias package for each of
+// them under the given output root, keeping the directory layout of the tree.
+// In each directory, the aliases are written to the file with the given name
+// using [Aliaser.GenerateFile], so the options are applied to all the
+// packages.
+//
+// The directory of each package is its path relative to the pattern root,
+// that is, the pattern without the trailing "/...". The target package name
+// is derived from the directory name (see [MirrorPackageName]), using the
+// name of the output root for the root package of the tree.
+//
+// The main packages, the internal ones, which cannot be imported from outside
+// the tree, and the packages without any object to alias are skipped.
+//
+// The pattern may also be relative to the directory in which the packages
+// are loaded (see [WithDir]), e.g. "./lib/...".
+//
+// Mirror returns the paths of the generated files, in the order of the
+// package paths, and an error if the pattern or the root are empty, the
+// packages loading fails, no package is in the tree ([ErrNoPackages]) or any
+// of the alias packages cannot be generated.
+// In the latter case, the files generated until the failure are kept.
+//
+// Example:
+//
+//	// github.com/example/lib
+//	// github.com/example/lib/codec
+//	// github.com/example/lib/codec/json
+//	aliaser.Mirror("github.com/example/lib/...", "pkg/lib", "alias.go")
+//
+//	// pkg/lib/alias.go (package lib)
+//	// pkg/lib/codec/alias.go (package codec)
+//	// pkg/lib/codec/json/alias.go (package json)
+func Mirror(pattern, root, name string, opts ...Option) ([]string, error) {
+	switch {
+	case pattern == "":
+		return nil, ErrEmptyPattern
+	case root == "":
+		return nil, ErrEmptyRoot
+	}
+	c := new(Config).setDefaults().applyOptions(opts...)
+	pkgs, err := packages.Load(c.packagesConfig(packages.NeedName|packages.NeedModule), pattern)
+	if err != nil {
+		return nil, fmt.Errorf("load packages: %w", err)
+	}
+	slices.SortFunc(pkgs, func(p1, p2 *packages.Package) int {
+		return strings.Compare(p1.PkgPath, p2.PkgPath)
+	})
+	base, err := c.mirrorBase(strings.TrimSuffix(pattern, "/..."), pkgs)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		files   []string
+		matched bool
+	)
+	for _, pkg := range pkgs {
+		if errs := pkg.Errors; len(errs) > 0 {
+			return files, fmt.Errorf("package errors: %s: %w", pkg.PkgPath, PackagesErrors(errs))
+		}
+		rel, ok := relPkgPath(base, pkg.PkgPath)
+		if !ok {
+			continue
+		}
+		matched = true
+		if pkg.Name == "main" || slices.Contains(strings.Split(rel, "/"), "internal") {
+			continue
+		}
+		dir, err := filepath.Abs(filepath.Join(root, filepath.FromSlash(rel)))
+		if err != nil {
+			return files, fmt.Errorf("abs: %w", err)
+		}
+		a, err := New(&Config{
+			TargetPackage: MirrorPackageName(filepath.Base(dir)),
+			Pattern:       pkg.PkgPath,
+		}, opts...)
+		if err != nil {
+			return files, fmt.Errorf("mirror %s: %w", pkg.PkgPath, err)
+		}
+		if a.empty() {
+			continue
+		}
+		file := filepath.Join(dir, name)
+		if err := a.GenerateFile(file); err != nil {
+			return files, fmt.Errorf("mirror %s: %w", pkg.PkgPath, err)
+		}
+		files = append(files, file)
+	}
+	if !matched {
+		return nil, fmt.Errorf("%w: %s", ErrNoPackages, pattern)
+	}
+	return files, nil
+}
+
+// mirrorBase returns the import path of the root of the tree matched by the
+// given pattern base. If the base is a directory, e.g. "./lib", its path is
+// resolved against the module of the loaded packages containing it.
+func (c *Config) mirrorBase(base string, pkgs []*packages.Package) (string, error) {
+	if !build.IsLocalImport(base) && !filepath.IsAbs(base) {
+		return base, nil
+	}
+	dir, err := filepath.Abs(filepath.Join(c.dir, base))
+	if err != nil {
+		return "", fmt.Errorf("abs: %w", err)
+	}
+	for _, pkg := range pkgs {
+		m := pkg.Module
+		if m == nil || m.Dir == "" {
+			continue
+		}
+		rel, err := filepath.Rel(m.Dir, dir)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			continue
+		}
+		return path.Join(m.Path, filepath.ToSlash(rel)), nil
+	}
+	return "", fmt.Errorf("%w: %s", ErrNoPackages, base)
+}
+
+// relPkgPath returns the path of the package relative to the given base path,
+// which is empty for the base package itself, and whether the package is in
+// the tree rooted at the base path.
+func relPkgPath(base, pkgPath string) (string, bool) {
+	if pkgPath == base {
+		return "", true
+	}
+	return strings.CutPrefix(pkgPath, base+"/")
+}
+
+// MirrorPackageName returns a valid package name derived from the given
+// directory name, as used by [Mirror]. The name is lowercased, the characters
+// that are not allowed in an identifier are replaced with underscores and an
+// underscore is appended to Go keywords or prepended if it starts with a
+// digit.
+//
+// Example:
+//
+//	MirrorPackageName("codec")   // "codec"
+//	MirrorPackageName("go-yaml") // "go_yaml"
+//	MirrorPackageName("v1.2")    // "v1_2"
+//	MirrorPackageName("type")    // "type_"
+//	MirrorPackageName("3d")      // "_3d"
+func MirrorPackageName(dir string) string {
+	name := strings.Map(func(r rune) rune {
+		if r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		return '_'
+	}, dir)
+	switch {
+	case token.IsKeyword(name):
+		return name + "_"
+	case unicode.IsDigit([]rune(name)[0]):
+		return "_" + name
+	}
+	return name
+}
+
+// empty reports whether the aliaser has no objects to alias.
+func (a *Aliaser) empty() bool {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return len(a.constants)+len(a.variables)+len(a.functions)+len(a.types)+len(a.platformFiles) == 0
+}
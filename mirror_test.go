@@ -0,0 +1,93 @@
+package aliaser
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestTreePattern is a valid pattern matching a package tree for testing.
+const TestTreePattern = "github.com/marcozac/go-aliaser/internal/testing/tree/..."
+
+func TestMirror(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "lib")
+	files, err := Mirror(TestTreePattern, root, "alias.go", AssignFunctions(true))
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		filepath.Join(root, "alias.go"),
+		filepath.Join(root, "go-sub", "alias.go"),
+	}, files)
+	data, err := os.ReadFile(files[0])
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "package lib\n")
+	assert.Contains(t, string(data), "Root = tree.Root")
+	data, err = os.ReadFile(files[1])
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "package go_sub\n")
+	assert.Contains(t, string(data), "Sub = sub.Sub")
+	for _, dir := range []string{"internal", "cmd", "empty"} {
+		assert.NoDirExists(t, filepath.Join(root, dir))
+	}
+	t.Run("SinglePackage", func(t *testing.T) {
+		root := filepath.Join(t.TempDir(), "single")
+		files, err := Mirror(TestPattern, root, "alias.go")
+		require.NoError(t, err)
+		assert.Equal(t, []string{filepath.Join(root, "alias.go")}, files)
+	})
+	t.Run("RelativePattern", func(t *testing.T) {
+		root := filepath.Join(t.TempDir(), "lib")
+		files, err := Mirror("./internal/testing/tree/...", root, "alias.go", AssignFunctions(true))
+		require.NoError(t, err)
+		assert.Equal(t, []string{
+			filepath.Join(root, "alias.go"),
+			filepath.Join(root, "go-sub", "alias.go"),
+		}, files)
+		t.Run("WithDir", func(t *testing.T) {
+			root := filepath.Join(t.TempDir(), "lib")
+			files, err := Mirror("./...", root, "alias.go", WithDir("internal/testing/tree"))
+			require.NoError(t, err)
+			assert.Len(t, files, 2)
+		})
+	})
+	t.Run("NoPackages", func(t *testing.T) {
+		_, err := Mirror(t.TempDir()+"/...", root, "alias.go")
+		assert.ErrorIs(t, err, ErrNoPackages)
+	})
+	t.Run("EmptyPattern", func(t *testing.T) {
+		_, err := Mirror("", root, "alias.go")
+		assert.ErrorIs(t, err, ErrEmptyPattern)
+	})
+	t.Run("EmptyRoot", func(t *testing.T) {
+		_, err := Mirror(TestTreePattern, "", "alias.go")
+		assert.ErrorIs(t, err, ErrEmptyRoot)
+	})
+	t.Run("Load", func(t *testing.T) {
+		t.Setenv("GOPACKAGESDRIVER", "fakedriver")
+		_, err := Mirror(TestTreePattern, root, "alias.go")
+		assert.Error(t, err)
+	})
+	t.Run("PackageErrors", func(t *testing.T) {
+		_, err := Mirror("golang.org/x/tools/go/*", root, "alias.go")
+		assert.ErrorContains(t, err, "package errors:")
+	})
+	t.Run("Aliaser", func(t *testing.T) {
+		_, err := Mirror("github.com/marcozac/go-aliaser/internal/testing/pkg/...", root, "alias.go", OnDeprecated(DeprecatedError))
+		assert.ErrorIs(t, err, ErrDeprecated)
+	})
+}
+
+func TestMirrorPackageName(t *testing.T) {
+	for dir, want := range map[string]string{
+		"codec":   "codec",
+		"Codec":   "codec",
+		"go-yaml": "go_yaml",
+		"v1.2":    "v1_2",
+		"type":    "type_",
+		"3d":      "_3d",
+	} {
+		assert.Equal(t, want, MirrorPackageName(dir), dir)
+	}
+}
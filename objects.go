@@ -2,10 +2,14 @@
 
 import (
 	"bytes"
+	"fmt"
 	"go/types"
+	"slices"
 	"strings"
+	"sync"
 
 	"github.com/marcozac/go-aliaser/importer"
+	"github.com/marcozac/go-aliaser/util/maps"
 	"github.com/marcozac/go-aliaser/util/sequence"
 )
 
@@ -33,12 +37,55 @@ func NewConst(c *types.Const, imp *importer.Importer) *Const {
 type Var struct {
 	*types.Var
 	objectResolver
+	strategy VarStrategy
+	setter   string
 }
 
 // NewVar returns a new [Var] with the given variable. The importer is used to
 // add the variable package to the list of imports.
 func NewVar(v *types.Var, imp *importer.Importer) *Var {
-	return &Var{v, newObjectResolver(v, imp)}
+	return &Var{Var: v, objectResolver: newObjectResolver(v, imp)}
+}
+
+// Strategy returns the strategy used to generate the alias of the variable.
+func (v *Var) Strategy() VarStrategy {
+	return v.strategy
+}
+
+// Pointer returns true if the alias of the variable is a pointer to the
+// original one (see [VarPointer]).
+func (v *Var) Pointer() bool {
+	return v.strategy == VarPointer
+}
+
+// Accessors returns true if the alias of the variable is a pair of getter and
+// setter functions (see [VarAccessors]).
+func (v *Var) Accessors() bool {
+	return v.strategy == VarAccessors
+}
+
+// TypeString returns the variable type as a string, as
+// [objectResolver.TypeString], but using the renamed aliases in place of the
+// original types, if any.
+func (v *Var) TypeString() string {
+	return types.TypeString(v.locals.apply(v.Type()), v.qualifier)
+}
+
+// SetterName returns the name of the setter function used by the
+// [VarAccessors] strategy. It is the name of the alias prefixed by "Set",
+// unless it has been changed to avoid a duplicate name (see [OnDuplicate]).
+func (v *Var) SetterName() string {
+	if v.setter != "" {
+		return v.setter
+	}
+	return "Set" + v.AliasName()
+}
+
+// SetterParam returns the name of the setter function parameter, ensuring it
+// does not conflict with the package aliases.
+func (v *Var) SetterParam() string {
+	taken := maps.Values(v.imp.AliasedImports())
+	return uniqueName("v", &taken)
 }
 
 var _ Object = (*Func)(nil)
@@ -49,13 +96,44 @@ func NewVar(v *types.Var, imp *importer.Importer) *Var {
 type Func struct {
 	*types.Func
 	objectResolver
-	tsig *Signature
+	tsig     *Signature
+	strategy FuncStrategy
 }
 
 // NewFunc returns a new [Func] with the given function. The importer is used to
 // add the function package to the list of imports.
 func NewFunc(fn *types.Func, imp *importer.Importer) *Func {
-	return &Func{fn, newObjectResolver(fn, imp), NewSignature(fn.Type().(*types.Signature), imp)}
+	tsig := NewSignature(fn.Type().(*types.Signature), imp)
+	tsig.outer = []string{fn.Name()}
+	return &Func{Func: fn, objectResolver: newObjectResolver(fn, imp), tsig: tsig}
+}
+
+// setAliasName sets the name of the alias of the function, that the wrapper
+// signature must not shadow.
+func (fn *Func) setAliasName(name string) {
+	fn.alias = name
+	fn.tsig.outer = []string{name}
+}
+
+// Strategy returns the strategy used to generate the alias of the function.
+// It is always [FuncWrap] for the generic functions.
+func (fn *Func) Strategy() FuncStrategy {
+	if fn.Generic() {
+		return FuncWrap
+	}
+	return fn.strategy
+}
+
+// Assigned returns true if the alias of the function is a variable assigned
+// with the original function (see [FuncAssign]).
+func (fn *Func) Assigned() bool {
+	return fn.Strategy() == FuncAssign
+}
+
+// TypeParams returns the type parameters of the function wrapper, renamed on
+// conflict as in [Signature.Wrapper].
+func (fn *Func) TypeParams() []*TypeParam {
+	return fn.tsig.typeParams()
 }
 
 // WriteSignature returns the signature of the function as a string. It is a wrapper
@@ -104,19 +182,300 @@ func (fn *Func) Returns() bool {
 type TypeName struct {
 	*types.TypeName
 	objectResolver
+	methods []*Method
 }
 
 // NewTypeName returns a new [TypeName] with the given type. The importer is used
 // to add the type package to the list of imports.
 func NewTypeName(tn *types.TypeName, imp *importer.Importer) *TypeName {
-	return &TypeName{tn, newObjectResolver(tn, imp)}
+	return &TypeName{TypeName: tn, objectResolver: newObjectResolver(tn, imp)}
+}
+
+// Methods returns the list of the methods to forward to the original type.
+// It is empty unless the forwarding is enabled for the type (see
+// [ForwardMethods]).
+func (tn *TypeName) Methods() []*Method {
+	return tn.methods
+}
+
+// forwardMethods sets the list of the methods to forward to the original type
+// to the exported methods declared by the original type for which keep
+// returns true, if the alias of the type is a new defined type. Otherwise, it
+// is a no-op, since the methods are already available through the alias.
+func (tn *TypeName) forwardMethods(keep func(*types.Func) bool) {
+	named, ok := tn.Type().(*types.Named)
+	if !ok || !tn.Generic() || tn.GenericAlias() {
+		return
+	}
+	sequence.New(named.NumMethods, named.Method).ForEach(func(fn *types.Func) {
+		if fn.Exported() && keep(fn) {
+			m := NewMethod(fn, tn, tn.imp)
+			m.docs = tn.docs
+			m.locals, m.tsig.locals = tn.locals, tn.locals
+			tn.methods = append(tn.methods, m)
+		}
+	})
+}
+
+var _ Object = (*Method)(nil)
+
+// Method is the type used to represent a method of a generic type whose alias
+// is a new defined type. Since a defined type does not inherit the methods of
+// the original one, the method is forwarded to the original type converting
+// the receiver and, if any, the parameters and results of the receiver type.
+//
+// Example:
+//
+//	type P[T any] pkg.P[T]
+//
+//	func (r *P[T]) Clone(p *P[T]) *P[T] {
+//		r0 := (*pkg.P[T])(r).Clone((*pkg.P[T])(p))
+//		return (*P[T])(r0)
+//	}
+type Method struct {
+	*Func
+	tn *TypeName
+
+	// base is the receiver base type, that is, the original type instantiated
+	// with the receiver type parameters. It must be substituted using
+	// [Signature.subst] before being compared with the wrapper types.
+	base types.Type
+
+	recvName    string
+	resultNames []string
+	once        sync.Once
+}
+
+// NewMethod returns a new [Method] with the given method of the given type
+// name. The importer is used to add the method package to the list of imports.
+func NewMethod(fn *types.Func, tn *TypeName, imp *importer.Importer) *Method {
+	m := &Method{Func: NewFunc(fn, imp), tn: tn}
+	m.tsig.outer = append(m.tsig.outer, tn.AliasName())
+	m.base = fn.Type().(*types.Signature).Recv().Type()
+	if ptr, ok := m.base.(*types.Pointer); ok {
+		m.base = ptr.Elem()
+	}
+	return m
+}
+
+// PointerReceiver returns true if the method has a pointer receiver.
+func (m *Method) PointerReceiver() bool {
+	_, ok := m.Type().(*types.Signature).Recv().Type().(*types.Pointer)
+	return ok
+}
+
+// Receiver returns the receiver of the forwarding method, using the alias
+// type name and the receiver type parameters.
+//
+// Example:
+//
+//	"r *P[T, V]"
+func (m *Method) Receiver() string {
+	m.setNames()
+	return m.recvName + " " + m.localType(m.PointerReceiver()).String()
+}
+
+// WriteSignature returns the signature of the method as [Func.WriteSignature],
+// but replacing the receiver base type with the alias one in the parameters
+// and results.
+func (m *Method) WriteSignature() string {
+	w := m.tsig.Wrapper()
+	sig := types.NewSignatureType(nil, nil, nil,
+		types.NewTuple(m.localVars(w.Params())...),
+		types.NewTuple(m.localVars(w.Results())...),
+		w.Variadic(),
+	)
+	buf := new(bytes.Buffer)
+	types.WriteSignature(buf, sig, m.qualifier)
+	return buf.String()
+}
+
+// Call returns the call to the original method, converting the receiver and
+// the parameters of the receiver type to the original type.
+//
+// Example:
+//
+//	"(*pkg.P[T, V])(r).Foo(a, (*pkg.P[T, V])(p))"
+func (m *Method) Call() string {
+	m.setNames()
+	params := m.tsig.Wrapper().Params()
+	args := sequence.New(params.Len, func(i int) string {
+		pv := params.At(i)
+		return m.convert(pv, pv.Name(), false)
+	}).Slice()
+	if m.tsig.Variadic() {
+		args[len(args)-1] += "..."
+	}
+	return fmt.Sprintf("%s.%s(%s)", conversion(m.originalType(m.PointerReceiver()), m.recvName), m.Name(), strings.Join(args, ", "))
+}
+
+// ConvertResults returns true if at least one of the results must be
+// converted to the alias type before being returned.
+func (m *Method) ConvertResults() bool {
+	results := m.tsig.Wrapper().Results()
+	for i := 0; i < results.Len(); i++ {
+		if _, ok := m.receiverType(results.At(i).Type()); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// ResultNames returns the names of the variables used to store the results
+// of the original method call, joined by a comma.
+//
+// Example:
+//
+//	"r0, r1"
+func (m *Method) ResultNames() string {
+	m.setNames()
+	return strings.Join(m.resultNames, ", ")
+}
+
+// ConvertedResults returns the results of the original method call,
+// converting those of the receiver type to the alias type.
+//
+// Example:
+//
+//	"(*P[T, V])(r0), r1"
+func (m *Method) ConvertedResults() string {
+	m.setNames()
+	results := m.tsig.Wrapper().Results()
+	converted := make([]string, results.Len())
+	for i := range converted {
+		converted[i] = m.convert(results.At(i), m.resultNames[i], true)
+	}
+	return strings.Join(converted, ", ")
+}
+
+// convert returns the given expression converted to the original type, or to
+// the local one if toLocal is true, if the variable has the receiver type.
+// Otherwise, it returns the expression as is.
+func (m *Method) convert(pv *types.Var, expr string, toLocal bool) string {
+	ptr, ok := m.receiverType(pv.Type())
+	switch {
+	case !ok:
+		return expr
+	case toLocal:
+		return conversion(m.localType(ptr), expr)
+	default:
+		return conversion(m.originalType(ptr), expr)
+	}
+}
+
+// conversion returns the conversion of the given expression to the given
+// type, wrapping the type in parentheses if it is a pointer.
+func conversion(typ types.Type, expr string) string {
+	if strings.HasPrefix(typ.String(), "*") {
+		return fmt.Sprintf("(%s)(%s)", typ, expr)
+	}
+	return fmt.Sprintf("%s(%s)", typ, expr)
+}
+
+// receiverType reports whether the given type is the receiver base type or a
+// pointer to it (ok) and, in this case, whether it is a pointer (ptr).
+func (m *Method) receiverType(typ types.Type) (ptr, ok bool) {
+	if p, isPtr := typ.(*types.Pointer); isPtr {
+		typ, ptr = p.Elem(), true
+	}
+	if !types.Identical(typ, m.tsig.subst(m.base)) {
+		return false, false
+	}
+	return ptr, true
+}
+
+// localVars returns the variables of the given tuple replacing the receiver
+// base type with the alias one.
+func (m *Method) localVars(tuple *types.Tuple) []*types.Var {
+	return sequence.FromSequenceable(tuple).SliceFunc(func(pv *types.Var) *types.Var {
+		if ptr, ok := m.receiverType(pv.Type()); ok {
+			return types.NewVar(pv.Pos(), pv.Pkg(), pv.Name(), m.localType(ptr))
+		}
+		return pv
+	})
+}
+
+// localType returns the alias type instantiated with the receiver type
+// parameters, as a pointer if ptr is true.
+func (m *Method) localType(ptr bool) types.Type {
+	return m.instance(m.tn.AliasName(), ptr)
+}
+
+// originalType returns the original type instantiated with the receiver type
+// parameters, as a pointer if ptr is true.
+func (m *Method) originalType(ptr bool) types.Type {
+	return m.instance(m.tn.PackageAlias()+"."+m.tn.Name(), ptr)
+}
+
+func (m *Method) instance(name string, ptr bool) types.Type {
+	m.tsig.Wrapper()
+	names := make([]string, len(m.tsig.recvTypeParams))
+	for i, tp := range m.tsig.recvTypeParams {
+		names[i] = tp.Obj().Name()
+	}
+	if ptr {
+		name = "*" + name
+	}
+	return &rawType{m.tsig.subst(m.base), name + "[" + strings.Join(names, ", ") + "]"}
+}
+
+// setNames sets the names of the receiver and of the results of the original
+// method call, ensuring they do not conflict with the package aliases, the
+// alias type name and the type parameters, parameters and results of the
+// method.
+func (m *Method) setNames() {
+	m.once.Do(func() {
+		w := m.tsig.Wrapper()
+		taken := append(maps.Values(m.imp.AliasedImports()), m.tn.AliasName())
+		for _, tp := range m.tsig.recvTypeParams {
+			taken = append(taken, tp.Obj().Name())
+		}
+		for _, tuple := range []*types.Tuple{w.Params(), w.Results()} {
+			sequence.FromSequenceable(tuple).ForEach(func(pv *types.Var) {
+				taken = append(taken, pv.Name())
+			})
+		}
+		m.recvName = uniqueName("r", &taken)
+		m.resultNames = make([]string, w.Results().Len())
+		for i := range m.resultNames {
+			m.resultNames[i] = uniqueName(fmt.Sprintf("r%d", i), &taken)
+		}
+	})
+}
+
+// uniqueName returns the given name, suffixed with underscores until it is not
+// in the taken list, and appends the result to the list.
+func uniqueName(name string, taken *[]string) string {
+	for slices.Contains(*taken, name) {
+		name += "_"
+	}
+	*taken = append(*taken, name)
+	return name
 }
 
 type objectResolver struct {
 	typeQualifier
 	orig       types.Object
-	typeParams []*TypeParam
+	alias      string
+	typeParams *types.TypeParamList
 	typeArgs   *sequence.Sequence[types.Type]
+
+	// renamedTypeParams are the type parameters renamed on conflict with the
+	// package aliases or the object name. They are set by the first call to
+	// TypeParams, after the package has been fully loaded.
+	renamedTypeParams []*TypeParam
+
+	// genericAlias is true if a generic object can be aliased using a
+	// parameterized type alias.
+	genericAlias bool
+
+	// docs is used to find the doc comment of the object. If nil, the object
+	// has no doc comment.
+	docs docFinder
+
+	// locals are the types declared in the generated code to be used in
+	// place of the original ones (see [localType]).
+	locals substitution
 }
 
 func newObjectResolver(obj types.Object, imp *importer.Importer) objectResolver {
@@ -126,12 +485,46 @@ func newObjectResolver(obj types.Object, imp *importer.Importer) objectResolver
 	return o
 }
 
+// AliasName returns the name of the alias declared in the generated code. It
+// is the name of the original object, unless it has been changed to resolve
+// a conflict with another object (see [OnDuplicatePrefix]).
+func (o *objectResolver) AliasName() string {
+	if o.alias == "" {
+		return o.orig.Name()
+	}
+	return o.alias
+}
+
 // PackageAlias returns the alias of the package as declared in the import
 // statement.
 func (o *objectResolver) PackageAlias() string {
 	return o.imp.AliasOf(o.orig.Pkg())
 }
 
+// Doc returns the doc comment of the original object, formatted as a
+// sequence of line comments, or an empty string if it has none. If the alias
+// has a different name and the comment starts with the original one, as by
+// convention, it is replaced with the alias name.
+func (o *objectResolver) Doc() string {
+	if o.docs == nil {
+		return ""
+	}
+	doc := o.docs.findDoc(o.orig)
+	if name := o.orig.Name(); o.AliasName() != name && strings.HasPrefix(doc, "// "+name+" ") {
+		doc = "// " + o.AliasName() + strings.TrimPrefix(doc, "// "+name)
+	}
+	return doc
+}
+
+// Deprecated returns the deprecation notice of the original object, formatted
+// as a sequence of line comments, or an empty string if it is not deprecated.
+func (o *objectResolver) Deprecated() string {
+	if o.docs == nil {
+		return ""
+	}
+	return o.docs.findDeprecation(o.orig)
+}
+
 // TypeString returns the object type as a string, resolving the package
 // names using the aliases declared in the import statements.
 func (o *objectResolver) TypeString() string {
@@ -147,16 +540,44 @@ func (o *objectResolver) TypeString() string {
 //	type B[T1 any] A[T1, int] // false
 //	type C A[int, string] // false
 func (o *objectResolver) Generic() bool {
-	tpl := len(o.TypeParams())
+	tpl := o.typeParams.Len()
 	return tpl > 0 && tpl > len(o.TypeArgs())
 }
 
-// TypeParams returns the type parameters of the object as a slice.
+// GenericAlias returns true if the object is generic and it can be aliased
+// using a parameterized type alias, preserving its method set and the
+// assignability to the original type. If it returns false for a generic
+// object, the alias must be declared as a new defined type instead.
+//
+// Example:
+//
+//	type N[T any] = pkg.N[T] // true
+//	type N[T any] pkg.N[T] // false
+func (o *objectResolver) GenericAlias() bool {
+	return o.genericAlias && o.Generic()
+}
+
+// TypeParams returns the type parameters of the object as a slice. They are
+// renamed if they conflict with the package aliases or with the object name,
+// so that they can be used in the declaration of the alias.
+//
+// Since it calls the [importer.Importer.AliasedImports] method for renaming
+// the type parameters and further calls will return the same ones, it should
+// be called only after the package has been fully loaded.
+//
+// Example:
+//
+//	type N[pkg_ any] pkg.N[pkg_] // type N[pkg any] struct{ Foo pkg }
 func (o *objectResolver) TypeParams() []*TypeParam {
-	if o.typeParams == nil {
+	if o.typeParams.Len() == 0 {
 		return nil
 	}
-	return o.typeParams
+	if o.renamedTypeParams == nil {
+		sc := newScope(append(maps.Values(o.imp.AliasedImports()), o.AliasName())...)
+		tps, _ := sc.declareTypeParams(o.typeParams, o.imp)
+		o.renamedTypeParams = wrapTypeParams(tps)
+	}
+	return o.renamedTypeParams
 }
 
 // TypeArgs returns the type arguments of the object as a slice.
@@ -211,11 +632,10 @@ func (o *objectResolver) importObject(pt PackageTyper) {
 
 func (o *objectResolver) setGenerics(typ types.Type) {
 	if typ, ok := typ.(interface{ TypeParams() *types.TypeParamList }); ok {
-		o.typeParams = make([]*TypeParam, typ.TypeParams().Len())
-		sequence.FromSequenceable(typ.TypeParams()).
-			ForEachIndex(func(tp *types.TypeParam, i int) {
+		o.typeParams = typ.TypeParams()
+		sequence.FromSequenceable(o.typeParams).
+			ForEach(func(tp *types.TypeParam) {
 				o.importType(tp.Constraint())
-				o.typeParams[i] = NewTypeParam(tp, o.imp)
 			})
 	}
 	if typ, ok := typ.(interface{ TypeArgs() *types.TypeList }); ok {
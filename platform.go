@@ -0,0 +1,418 @@
+package aliaser
+
+import (
+	"errors"
+	"fmt"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/marcozac/go-aliaser/util/maps"
+	"github.com/marcozac/go-aliaser/util/sequence"
+)
+
+// Platform is a target platform of the generated code, defined by an
+// operating system, an architecture and a set of build tags. The empty fields
+// are not constrained: the packages are loaded using the values of the
+// current environment and the generated build constraint does not include
+// them.
+//
+// Example:
+//
+//	aliaser.Platform{GOOS: "linux", GOARCH: "amd64"} // linux && amd64
+//	aliaser.Platform{GOOS: "windows"}                // windows
+//	aliaser.Platform{Tags: []string{"netgo"}}        // netgo
+type Platform struct {
+	// GOOS is the target operating system, e.g. "linux".
+	GOOS string
+
+	// GOARCH is the target architecture, e.g. "amd64".
+	GOARCH string
+
+	// Tags is the list of the build tags satisfied by the platform.
+	Tags []string
+}
+
+// ParsePlatform parses a platform in the form "GOOS[/GOARCH][:tag,...]", as
+// returned by [Platform.String]. The operating system may be omitted only if
+// at least one build tag is given.
+//
+// Example:
+//
+//	ParsePlatform("linux")                // {GOOS: "linux"}
+//	ParsePlatform("linux/arm64:netgo")    // {GOOS: "linux", GOARCH: "arm64", Tags: ["netgo"]}
+//	ParsePlatform(":integration,purego") // {Tags: ["integration", "purego"]}
+func ParsePlatform(s string) (Platform, error) {
+	var p Platform
+	target, tags, ok := strings.Cut(s, ":")
+	if ok && tags != "" {
+		p.Tags = strings.Split(tags, ",")
+	}
+	p.GOOS, p.GOARCH, _ = strings.Cut(target, "/")
+	if err := p.validate(); err != nil {
+		return Platform{}, fmt.Errorf("%w: %q: %w", ErrInvalidPlatform, s, err)
+	}
+	return p, nil
+}
+
+// String returns the platform in the form "GOOS[/GOARCH][:tag,...]".
+func (p Platform) String() string {
+	s := p.GOOS
+	if p.GOARCH != "" {
+		s += "/" + p.GOARCH
+	}
+	if len(p.Tags) > 0 {
+		s += ":" + strings.Join(p.Tags, ",")
+	}
+	return s
+}
+
+// Constraint returns the build constraint expression satisfied only by the
+// platform, that is, the conjunction of its operating system, architecture
+// and build tags.
+//
+// Example:
+//
+//	Platform{GOOS: "linux", GOARCH: "amd64", Tags: []string{"netgo"}}.Constraint()
+//	// linux && amd64 && netgo
+func (p Platform) Constraint() string {
+	return strings.Join(p.terms(), " && ")
+}
+
+// terms returns the non-empty terms of the platform build constraint.
+func (p Platform) terms() []string {
+	terms := make([]string, 0, len(p.Tags)+2)
+	for _, t := range append([]string{p.GOOS, p.GOARCH}, p.Tags...) {
+		if t != "" {
+			terms = append(terms, t)
+		}
+	}
+	return terms
+}
+
+// validate returns an error if the platform has no terms or any of them is
+// not a valid build tag.
+func (p Platform) validate() error {
+	switch {
+	case p.GOOS == "" && p.GOARCH != "":
+		return errors.New("architecture without operating system")
+	case len(p.terms()) == 0:
+		return errors.New("no operating system or build tags")
+	}
+	for _, t := range append([]string{p.GOOS, p.GOARCH}, p.Tags...) {
+		if t == "" {
+			continue
+		}
+		if strings.IndexFunc(t, func(r rune) bool {
+			return !(r == '_' || r == '.' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9')
+		}) >= 0 {
+			return fmt.Errorf("invalid build tag %q", t)
+		}
+	}
+	return nil
+}
+
+// env returns the environment variables used to load the packages for the
+// platform.
+func (p Platform) env() []string {
+	var env []string
+	if p.GOOS != "" {
+		env = append(env, "GOOS="+p.GOOS)
+	}
+	if p.GOARCH != "" {
+		env = append(env, "GOARCH="+p.GOARCH)
+	}
+	return env
+}
+
+// buildFlags returns the build flags used to load the packages for the
+// platform.
+func (p Platform) buildFlags() []string {
+	if len(p.Tags) == 0 {
+		return nil
+	}
+	return []string{"-tags=" + strings.Join(p.Tags, ",")}
+}
+
+// WithPlatforms sets the target platforms of the generated code. The packages
+// are loaded once for each platform and the objects declared on all of them
+// are generated as usual. Instead, the objects declared only on some of them,
+// or declared with different types, are generated by [Aliaser.GenerateFile]
+// into separate files guarded by a build constraint satisfied only by those
+// platforms (see [PlatformFileName]). Since the objects declared on all the
+// given platforms may not be declared on the others, the file of the shared
+// objects is guarded by a build constraint satisfied by any of them.
+//
+// The platforms should be mutually exclusive, since an object declared with
+// different types on two platforms satisfied by the same build would be
+// declared twice.
+//
+// Example:
+//
+//	a, _ := aliaser.New(&aliaser.Config{
+//		TargetPackage: "foo",
+//		Pattern:       "github.com/example/sys",
+//	}, aliaser.WithPlatforms(
+//		aliaser.Platform{GOOS: "linux"},
+//		aliaser.Platform{GOOS: "darwin"},
+//	))
+//	a.GenerateFile("foo/alias.go")
+//
+//	// foo/alias.go: //go:build linux || darwin, the objects declared on both
+//	// foo/alias_linux.go: //go:build linux
+//	// foo/alias_darwin.go: //go:build darwin
+func WithPlatforms(platforms ...Platform) Option {
+	return option(func(c *Config) {
+		c.platforms = append(c.platforms, platforms...)
+	})
+}
+
+// PlatformFileName returns the name of the file where the objects declared
+// only on the given platforms are generated, deriving it from the name of the
+// file of the shared objects.
+//
+// For a single platform without build tags, the name is suffixed with its
+// operating system and architecture, as by the Go convention. Otherwise, the
+// terms of all the platforms are followed by "_build", so that the name does
+// not imply a narrower constraint than the one declared in the file.
+//
+// Example:
+//
+//	PlatformFileName("alias.go", Platform{GOOS: "linux"})                    // alias_linux.go
+//	PlatformFileName("alias.go", Platform{GOOS: "linux", GOARCH: "arm64"})   // alias_linux_arm64.go
+//	PlatformFileName("alias.go", Platform{GOOS: "linux"}, Platform{GOOS: "darwin"}) // alias_linux_darwin_build.go
+func PlatformFileName(name string, platforms ...Platform) string {
+	ext := filepath.Ext(name)
+	var terms []string
+	for _, p := range platforms {
+		terms = append(terms, p.terms()...)
+	}
+	if len(platforms) != 1 || len(platforms[0].Tags) > 0 || platforms[0].GOOS == "" {
+		terms = append(terms, "build")
+	}
+	return strings.TrimSuffix(name, ext) + "_" + strings.Join(terms, "_") + ext
+}
+
+// platformConstraint returns the build constraint satisfied by any of the
+// given platforms.
+func platformConstraint(platforms []Platform) string {
+	exprs := make([]string, len(platforms))
+	for i, p := range platforms {
+		exprs[i] = p.Constraint()
+		if len(platforms) > 1 && len(p.terms()) > 1 {
+			exprs[i] = "(" + exprs[i] + ")"
+		}
+	}
+	return strings.Join(exprs, " || ")
+}
+
+// platformFile is a set of objects generated into the same file, since they
+// are declared on the same platforms.
+type platformFile struct {
+	// platforms is the list of the platforms where the objects are declared.
+	platforms []Platform
+
+	// view is the aliaser used to generate the file. It shares the importer
+	// and the doc comments of the aliaser loaded for the first platform.
+	view *Aliaser
+}
+
+// loadPlatforms loads the packages once for each target platform and splits
+// the loaded objects into the shared ones, which are set as the objects of
+// the aliaser, and the platform specific ones, grouped into files by the
+// platforms where they are declared.
+func (a *Aliaser) loadPlatforms() error {
+	loaded := make([]*Aliaser, len(a.platforms))
+	for i, p := range a.platforms {
+		if err := p.validate(); err != nil {
+			return fmt.Errorf("%w: %q: %w", ErrInvalidPlatform, p, err)
+		}
+		c := *a.Config
+		c.platforms, c.platform = nil, &a.platforms[i]
+		pa := &Aliaser{
+			Config:   &c,
+			Importer: c.newImporter(),
+			names:    maps.NewSafe(make(map[string]objectId)),
+			docs:     make(map[token.Pos]*objectDoc),
+			locals:   make(substitution),
+		}
+		for _, name := range a.targetNames {
+			pa.names.Put(name, targetId)
+		}
+		if err := pa.load(); err != nil {
+			return fmt.Errorf("platform %s: %w", p, err)
+		}
+		loaded[i] = pa
+	}
+	a.splitPlatforms(loaded)
+	// the listed platforms are usually not exhaustive, so the shared objects
+	// may not be declared on the other ones
+	a.buildConstraint = platformConstraint(a.platforms)
+	return nil
+}
+
+// splitPlatforms sets the objects declared on all the given platform aliasers
+// as the objects of the aliaser, using the importer of the first one, and
+// groups the others by the platforms where they are declared.
+//
+// Two objects loaded for different platforms are the same if they have the
+// same kind, alias name and generated declaration (see platformKey).
+func (a *Aliaser) splitPlatforms(loaded []*Aliaser) {
+	objs := make(map[string][]Object) // key -> object by platform index
+	var keys []string
+	for i, pa := range loaded {
+		for _, o := range pa.objects() {
+			key := platformKey(o)
+			if _, ok := objs[key]; !ok {
+				objs[key] = make([]Object, len(loaded))
+				keys = append(keys, key)
+			}
+			objs[key][i] = o
+		}
+	}
+	files := make(map[string]*platformFile)
+	var masks [][]int
+	for _, key := range keys {
+		var mask []int
+		for i, o := range objs[key] {
+			if o != nil {
+				mask = append(mask, i)
+			}
+		}
+		if len(mask) == len(loaded) {
+			a.addLoaded(objs[key][0])
+			continue
+		}
+		id := fmt.Sprint(mask)
+		pf, ok := files[id]
+		if !ok {
+			c := *a.Config
+			c.platforms = nil
+			pf = &platformFile{
+				view: &Aliaser{
+					Config:   &c,
+					Importer: loaded[mask[0]].Importer,
+					names:    loaded[mask[0]].names,
+					docs:     loaded[mask[0]].docs,
+				},
+			}
+			for _, i := range mask {
+				pf.platforms = append(pf.platforms, a.platforms[i])
+			}
+			c.buildConstraint = platformConstraint(pf.platforms)
+			files[id] = pf
+			masks = append(masks, mask)
+		}
+		pf.view.addLoaded(objs[key][mask[0]])
+	}
+	slices.SortFunc(masks, slices.Compare)
+	for _, mask := range masks {
+		a.platformFiles = append(a.platformFiles, files[fmt.Sprint(mask)])
+	}
+	a.Importer, a.docs = loaded[0].Importer, loaded[0].docs
+}
+
+// objects returns all the objects loaded for aliasing, in the order of the
+// generated code.
+func (a *Aliaser) objects() []Object {
+	var objs []Object
+	for _, c := range a.constants {
+		objs = append(objs, c)
+	}
+	for _, v := range a.variables {
+		objs = append(objs, v)
+	}
+	for _, fn := range a.functions {
+		objs = append(objs, fn)
+	}
+	for _, tn := range a.types {
+		objs = append(objs, tn)
+	}
+	return objs
+}
+
+// addLoaded adds an object already loaded by another aliaser to the list of
+// its kind, declaring its alias name.
+func (a *Aliaser) addLoaded(o Object) {
+	switch o := o.(type) {
+	case *Const:
+		a.names.PutNX(o.AliasName(), constantId)
+		a.constants = append(a.constants, o)
+	case *Var:
+		a.names.PutNX(o.AliasName(), variableId)
+		if o.Accessors() {
+			a.names.PutNX(o.SetterName(), setterId)
+		}
+		a.variables = append(a.variables, o)
+	case *Func:
+		a.names.PutNX(o.AliasName(), functionId)
+		a.functions = append(a.functions, o)
+	case *TypeName:
+		a.names.PutNX(o.AliasName(), typeId)
+		a.types = append(a.types, o)
+	}
+}
+
+// platformKey returns a key identifying the given object among the ones
+// loaded for different platforms. It is made of the kind and the alias name
+// of the object and, if the generated declaration depends on its type, of
+// the type qualified with the full package paths, so that it does not depend
+// on the package aliases.
+//
+// Example:
+//
+//	const Foo = pkg.Foo // "const Foo", whatever the type and value
+//	func Bar(fd int) error // "func Bar func(int) error"
+func platformKey(o Object) string {
+	switch o := o.(type) {
+	case *Const:
+		return "const " + o.AliasName()
+	case *Var:
+		key := "var " + o.AliasName() + " " + o.Strategy().String()
+		if o.Accessors() {
+			key += " " + types.TypeString(o.Type(), pathQualifier)
+		}
+		return key
+	case *Func:
+		key := "func " + o.AliasName()
+		if !o.Assigned() {
+			key += " " + signatureKey(o.Type().(*types.Signature))
+		}
+		return key
+	case *TypeName:
+		key := "type " + o.AliasName() + " " + types.TypeString(o.Type(), pathQualifier)
+		for _, m := range o.Methods() {
+			key += "; " + m.Name() + " " + signatureKey(m.Type().(*types.Signature))
+		}
+		return key
+	}
+	return ""
+}
+
+// pathQualifier is a [types.Qualifier] that qualifies the objects with the
+// full path of their package.
+func pathQualifier(pkg *types.Package) string {
+	return pkg.Path()
+}
+
+// signatureKey returns the given signature as a string, ignoring the names
+// of the parameters and results and the receiver.
+func signatureKey(sig *types.Signature) string {
+	unnamed := func(tuple *types.Tuple) *types.Tuple {
+		vars := sequence.FromSequenceable(tuple).Slice()
+		for i, v := range vars {
+			vars[i] = types.NewVar(v.Pos(), v.Pkg(), "", v.Type())
+		}
+		return types.NewTuple(vars...)
+	}
+	var constraints []string
+	sequence.FromSequenceable(sig.TypeParams()).ForEach(func(tp *types.TypeParam) {
+		constraints = append(constraints, types.TypeString(tp.Constraint(), pathQualifier))
+	})
+	return fmt.Sprintf("[%s]%s", strings.Join(constraints, ", "), types.TypeString(
+		types.NewSignatureType(nil, nil, nil, unnamed(sig.Params()), unnamed(sig.Results()), sig.Variadic()),
+		pathQualifier,
+	))
+}
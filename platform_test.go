@@ -0,0 +1,151 @@
+package aliaser
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPlatformPattern is a valid pattern for testing the platform-aware
+// generation.
+const TestPlatformPattern = "github.com/marcozac/go-aliaser/internal/testing/platform"
+
+func TestPlatform(t *testing.T) {
+	t.Run("Parse", func(t *testing.T) {
+		for s, want := range map[string]Platform{
+			"linux":               {GOOS: "linux"},
+			"linux/arm64":         {GOOS: "linux", GOARCH: "arm64"},
+			"linux/arm64:netgo":   {GOOS: "linux", GOARCH: "arm64", Tags: []string{"netgo"}},
+			":integration,purego": {Tags: []string{"integration", "purego"}},
+		} {
+			p, err := ParsePlatform(s)
+			require.NoError(t, err, s)
+			assert.Equal(t, want, p)
+			assert.Equal(t, s, p.String())
+		}
+		for _, s := range []string{"", ":", "/amd64", "linux/amd 64", "linux:foo-bar"} {
+			_, err := ParsePlatform(s)
+			assert.ErrorIs(t, err, ErrInvalidPlatform, s)
+		}
+	})
+	t.Run("Constraint", func(t *testing.T) {
+		assert.Equal(t, "linux && amd64 && netgo", Platform{GOOS: "linux", GOARCH: "amd64", Tags: []string{"netgo"}}.Constraint())
+		assert.Equal(t, "linux || (darwin && arm64)", platformConstraint([]Platform{
+			{GOOS: "linux"},
+			{GOOS: "darwin", GOARCH: "arm64"},
+		}))
+	})
+	t.Run("FileName", func(t *testing.T) {
+		assert.Equal(t, "alias_linux.go", PlatformFileName("alias.go", Platform{GOOS: "linux"}))
+		assert.Equal(t, "alias_linux_arm64.go", PlatformFileName("alias.go", Platform{GOOS: "linux", GOARCH: "arm64"}))
+		assert.Equal(t, "alias_linux_foo_build.go", PlatformFileName("alias.go", Platform{GOOS: "linux", Tags: []string{"foo"}}))
+		assert.Equal(t, "alias_linux_darwin_build.go", PlatformFileName("alias.go", Platform{GOOS: "linux"}, Platform{GOOS: "darwin"}))
+	})
+}
+
+func TestWithPlatforms(t *testing.T) {
+	newAliaser := func(t *testing.T, opts ...Option) *Aliaser {
+		t.Helper()
+		a, err := New(&Config{TargetPackage: "foo", Pattern: TestPlatformPattern}, opts...)
+		require.NoError(t, err)
+		return a
+	}
+	a := newAliaser(t, WithPlatforms(
+		Platform{GOOS: "linux"},
+		Platform{GOOS: "darwin"},
+		Platform{GOOS: "windows"},
+	))
+	dir := t.TempDir()
+	name := filepath.Join(dir, "alias.go")
+	require.NoError(t, a.GenerateFile(name))
+	files := map[string][]string{
+		"alias.go":                    {"//go:build linux || darwin || windows\n", "Shared = platform.Shared", "func Common(s string) string {"},
+		"alias_linux.go":              {"//go:build linux\n", "func Linux() {", "func Sys(fd int) error {"},
+		"alias_darwin.go":             {"//go:build darwin\n", "func Darwin() {", "func Sys(fd uintptr) error {"},
+		"alias_windows.go":            {"//go:build windows\n", "func Sys(handle uintptr, name string) error {"},
+		"alias_linux_darwin_build.go": {"//go:build linux || darwin\n", "func Unix() {"},
+	}
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, entries, len(files))
+	for file, contains := range files {
+		data, err := os.ReadFile(filepath.Join(dir, file))
+		require.NoError(t, err, file)
+		for _, s := range contains {
+			assert.Contains(t, string(data), s, file)
+		}
+	}
+	data, err := os.ReadFile(name)
+	require.NoError(t, err)
+	assert.Equal(t, 1, strings.Count(string(data), "go:build"))
+	assert.NotContains(t, string(data), "func Sys(")
+	t.Run("Generate", func(t *testing.T) {
+		buf := new(bytes.Buffer)
+		require.NoError(t, a.Generate(buf))
+		assert.Equal(t, string(data), buf.String())
+	})
+	t.Run("GenerateFS", func(t *testing.T) {
+		var mfs MemFS
+		generated, err := a.GenerateFS(&mfs, "foo/alias.go")
+		require.NoError(t, err)
+		require.Len(t, generated, len(files))
+		for _, gf := range generated {
+			assert.True(t, gf.Changed, gf.Name)
+			data, err := mfs.ReadFile(gf.Name)
+			require.NoError(t, err)
+			expected, err := os.ReadFile(filepath.Join(dir, filepath.Base(gf.Name)))
+			require.NoError(t, err)
+			assert.Equal(t, string(expected), string(data), gf.Name)
+		}
+		assert.Len(t, mfs.Names(), len(files))
+	})
+	t.Run("Unchanged", func(t *testing.T) {
+		generated, err := a.GenerateFiles(name)
+		require.NoError(t, err)
+		require.Len(t, generated, len(files))
+		assert.Equal(t, name, generated[0].Name)
+		for _, gf := range generated {
+			assert.Contains(t, files, filepath.Base(gf.Name))
+			assert.False(t, gf.Changed, gf.Name)
+		}
+	})
+	t.Run("Tags", func(t *testing.T) {
+		a := newAliaser(t, WithPlatforms(
+			Platform{GOOS: "linux", Tags: []string{"foo"}},
+			Platform{GOOS: "darwin"},
+		))
+		dir := t.TempDir()
+		require.NoError(t, a.GenerateFile(filepath.Join(dir, "alias.go")))
+		data, err := os.ReadFile(filepath.Join(dir, "alias_linux_foo_build.go"))
+		require.NoError(t, err)
+		assert.Contains(t, string(data), "//go:build linux && foo\n")
+		assert.Contains(t, string(data), "func Foo() {")
+		data, err = os.ReadFile(filepath.Join(dir, "alias.go"))
+		require.NoError(t, err)
+		assert.Contains(t, string(data), "//go:build (linux && foo) || darwin\n")
+		assert.Contains(t, string(data), "func Unix() {")
+	})
+	t.Run("AssignFunctions", func(t *testing.T) {
+		a := newAliaser(t, AssignFunctions(true), WithPlatforms(
+			Platform{GOOS: "linux"},
+			Platform{GOOS: "darwin"},
+		))
+		buf := new(bytes.Buffer)
+		require.NoError(t, a.Generate(buf))
+		assert.Contains(t, buf.String(), "Sys = platform.Sys")
+	})
+	t.Run("Invalid", func(t *testing.T) {
+		_, err := New(&Config{TargetPackage: "foo", Pattern: TestPlatformPattern}, WithPlatforms(Platform{}))
+		assert.ErrorIs(t, err, ErrInvalidPlatform)
+	})
+	t.Run("Load", func(t *testing.T) {
+		t.Setenv("GOPACKAGESDRIVER", "fakedriver")
+		_, err := New(&Config{TargetPackage: "foo", Pattern: TestPlatformPattern}, WithPlatforms(Platform{GOOS: "linux"}))
+		assert.ErrorContains(t, err, "platform linux:")
+	})
+}
@@ -0,0 +1,107 @@
+package aliaser
+
+import "regexp"
+
+// renameRule is a regular expression replacement applied to the alias names.
+type renameRule struct {
+	re   *regexp.Regexp
+	repl string
+}
+
+// renamer is the set of renaming rules applied to the alias names.
+type renamer struct {
+	names  map[string]string
+	rules  []renameRule
+	prefix string
+	suffix string
+}
+
+// rename returns the alias name of the object with the given name. If the
+// name is mapped explicitly (see [RenameNames]), the mapped name is returned
+// as is. Otherwise, the regular expression replacements are applied in order
+// (see [RenameRegexp]) and the result is prefixed and suffixed (see
+// [RenamePrefix] and [RenameSuffix]).
+func (r *renamer) rename(name string) string {
+	if n, ok := r.names[name]; ok {
+		return n
+	}
+	for _, rule := range r.rules {
+		name = rule.re.ReplaceAllString(name, rule.repl)
+	}
+	return r.prefix + name + r.suffix
+}
+
+// RenamePrefix sets the prefix added to the names of all the aliases, except
+// the ones mapped explicitly by [RenameNames]. The renamed types are also used
+// in place of the original ones in the generated wrappers.
+//
+// Example:
+//
+//	// RenamePrefix("HTTP")
+//	type HTTPClient = pkg.Client
+//
+//	func HTTPNewClient() *HTTPClient {
+//		return pkg.NewClient()
+//	}
+func RenamePrefix(prefix string) Option {
+	return option(func(c *Config) {
+		c.renamer.prefix = prefix
+	})
+}
+
+// RenameSuffix sets the suffix added to the names of all the aliases, except
+// the ones mapped explicitly by [RenameNames], after the prefix and the
+// regular expression replacements are applied.
+//
+// Example:
+//
+//	// RenameSuffix("V2")
+//	const VersionV2 = pkg.Version
+func RenameSuffix(suffix string) Option {
+	return option(func(c *Config) {
+		c.renamer.suffix = suffix
+	})
+}
+
+// RenameRegexp adds a replacement of the matches of the given regular
+// expression in the names of the aliases, except the ones mapped explicitly by
+// [RenameNames]. The replacement string may refer to the submatches as in
+// [regexp.Regexp.ReplaceAllString]. The replacements are applied in the order
+// they are added, before the prefix and the suffix.
+//
+// Example:
+//
+//	// RenameRegexp(regexp.MustCompile(`Client`), "HTTPClient")
+//	type HTTPClient = pkg.Client
+//
+//	func NewHTTPClient() *HTTPClient {
+//		return pkg.NewClient()
+//	}
+func RenameRegexp(re *regexp.Regexp, repl string) Option {
+	return option(func(c *Config) {
+		c.renamer.rules = append(c.renamer.rules, renameRule{re, repl})
+	})
+}
+
+// RenameNames maps the names of the given objects to the names of their
+// aliases. An explicitly mapped name takes precedence over any other
+// renaming rule, that is not applied to it.
+//
+// Example:
+//
+//	// RenameNames(map[string]string{"Client": "HTTPClient"})
+//	type HTTPClient = pkg.Client
+//
+//	func NewClient() *HTTPClient {
+//		return pkg.NewClient()
+//	}
+func RenameNames(names map[string]string) Option {
+	return option(func(c *Config) {
+		if c.renamer.names == nil {
+			c.renamer.names = make(map[string]string, len(names))
+		}
+		for name, alias := range names {
+			c.renamer.names[name] = alias
+		}
+	})
+}
@@ -0,0 +1,86 @@
+package aliaser
+
+import (
+	"bytes"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestRenamePattern is a valid pattern for testing the renaming rules.
+const TestRenamePattern = "github.com/marcozac/go-aliaser/internal/testing/rename"
+
+func TestRenamer(t *testing.T) {
+	r := &renamer{
+		names: map[string]string{"Foo": "Bar"},
+		rules: []renameRule{
+			{regexp.MustCompile(`^New(\w+)$`), "Make$1"},
+			{regexp.MustCompile(`Client`), "HTTPClient"},
+		},
+		prefix: "X",
+		suffix: "V2",
+	}
+	assert.Equal(t, "Bar", r.rename("Foo"))
+	assert.Equal(t, "XMakeHTTPClientV2", r.rename("NewClient"))
+	assert.Equal(t, "XBazV2", r.rename("Baz"))
+	assert.Equal(t, "Baz", new(renamer).rename("Baz"))
+}
+
+func TestRenameOptions(t *testing.T) {
+	generate := func(t *testing.T, opts ...Option) string {
+		t.Helper()
+		a, err := New(&Config{TargetPackage: TestTarget, Pattern: TestRenamePattern}, opts...)
+		require.NoError(t, err)
+		buf := new(bytes.Buffer)
+		require.NoError(t, a.Generate(buf))
+		return buf.String()
+	}
+	t.Run("Regexp", func(t *testing.T) {
+		out := generate(t, RenameRegexp(regexp.MustCompile(`Client`), "HTTPClient"), WithVarStrategy(VarAccessors))
+		assert.Contains(t, out, "HTTPClient = rename.Client")
+		assert.Contains(t, out, "func NewHTTPClient() *HTTPClient {\n\treturn rename.NewClient()\n}")
+		assert.Contains(t, out, "func NewHTTPClientWithOptions(opts rename.Options[*HTTPClient]) *HTTPClient {")
+		assert.Contains(t, out, "func HTTPClients(cs ...*HTTPClient) map[string]*HTTPClient {")
+		assert.Contains(t, out, "func DefaultHTTPClient() *HTTPClient {")
+		assert.Contains(t, out, "func SetDefaultHTTPClient(v *HTTPClient) {")
+		assert.Contains(t, out, "Version = rename.Version")
+	})
+	t.Run("PrefixSuffix", func(t *testing.T) {
+		out := generate(t, RenamePrefix("My"), RenameSuffix("V2"))
+		assert.Contains(t, out, "MyVersionV2 = rename.Version")
+		assert.Contains(t, out, "MyClientV2 = rename.Client")
+		assert.Contains(t, out, "func MyNewClientV2() *MyClientV2 {")
+		// defined types are not identical to the original ones
+		assert.Contains(t, out, "MyOptionsV2[T any] rename.Options[T]")
+		assert.Contains(t, out, "(opts rename.Options[*MyClientV2]) *MyClientV2 {")
+	})
+	t.Run("Names", func(t *testing.T) {
+		out := generate(t, RenameNames(map[string]string{"Client": "HTTPClient"}), RenamePrefix("X"))
+		assert.Contains(t, out, "HTTPClient = rename.Client")
+		assert.Contains(t, out, "func XNewClient() *HTTPClient {")
+	})
+	t.Run("GenericAlias", func(t *testing.T) {
+		out := generate(t, WithGoVersion(GenericAliasVersion), RenamePrefix("X"))
+		assert.Contains(t, out, "XOptions[T any] = rename.Options[T]")
+		assert.Contains(t, out, "(opts XOptions[*XClient]) *XClient {")
+	})
+	t.Run("Replace", func(t *testing.T) {
+		a, err := New(
+			&Config{TargetPackage: TestTarget, Pattern: TestRenamePattern},
+			RenameNames(map[string]string{"Client": "HTTPClient"}),
+			OnDuplicate(OnDuplicateReplace),
+		)
+		require.NoError(t, err)
+		require.Len(t, a.locals, 1)
+		a.mu.Lock()
+		a.deleteObject("HTTPClient", typeId)
+		a.mu.Unlock()
+		assert.Empty(t, a.locals)
+	})
+	t.Run("InvalidName", func(t *testing.T) {
+		_, err := New(&Config{TargetPackage: TestTarget, Pattern: TestRenamePattern}, RenameSuffix("-"))
+		assert.ErrorIs(t, err, ErrInvalidAliasName)
+	})
+}
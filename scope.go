@@ -0,0 +1,281 @@
+package aliaser
+
+import (
+	"fmt"
+	"go/types"
+	"slices"
+
+	"github.com/marcozac/go-aliaser/importer"
+	"github.com/marcozac/go-aliaser/util/sequence"
+)
+
+// scope is a scope of the generated code, such as a function signature or a
+// generic type declaration. It is used to rename the identifiers declared in
+// it, like type parameters, parameters and results, so that they neither
+// shadow the identifiers of the outer scopes used by the generated code, e.g.
+// the package aliases and the name of the declared object, nor conflict with
+// each other.
+//
+// Example:
+//
+//	import (
+//		"encoding/json"
+//
+//		"example.com/pkg"
+//	)
+//
+//	// func F[pkg any](json pkg, pkg_ string)
+//	func F[pkg_ any](json_ pkg_, pkg__ string) {
+//		pkg.F[pkg_](json_, pkg__)
+//	}
+type scope struct {
+	// outer is the list of the names that must not be shadowed by the names
+	// declared in the scope.
+	outer []string
+
+	// taken is the list of the names that cannot be used to rename an
+	// identifier, since they are in use in the scope or in the outer ones.
+	taken []string
+}
+
+// newScope returns a new [scope] nested in an outer one declaring the given
+// names.
+func newScope(outer ...string) *scope {
+	return &scope{outer: slices.Clone(outer), taken: slices.Clone(outer)}
+}
+
+// reserve marks the given names as taken, so that no renamed identifier will
+// use them, without declaring them in the scope.
+func (s *scope) reserve(names ...string) {
+	for _, name := range names {
+		if name != "" && name != "_" {
+			s.taken = append(s.taken, name)
+		}
+	}
+}
+
+// declare declares the given names in the scope and returns them renamed. A
+// name shadowing an outer one is suffixed with underscores until it is not
+// taken. If synthesize is true, empty and blank names are replaced with "p"
+// followed by their index, suffixed as above if needed. Otherwise, they are
+// returned as is. The returned names must not be shadowed by the ones declared
+// later in the same scope.
+func (s *scope) declare(names []string, synthesize bool) []string {
+	s.reserve(names...)
+	renamed := make([]string, len(names))
+	for i, name := range names {
+		switch {
+		case name == "" || name == "_":
+			if synthesize {
+				name = uniqueName(fmt.Sprintf("p%d", i), &s.taken)
+			}
+		case slices.Contains(s.outer, name):
+			name = uniqueName(name, &s.taken)
+		}
+		if name != "" && name != "_" {
+			s.outer = append(s.outer, name)
+		}
+		renamed[i] = name
+	}
+	return renamed
+}
+
+// declareTuple declares the variables of the given tuple in the scope as
+// [scope.declare] and returns a new tuple with the renamed variables, whose
+// types are substituted using the given substitution.
+func (s *scope) declareTuple(tuple *types.Tuple, sub substitution, synthesize bool) *types.Tuple {
+	vars := sequence.FromSequenceable(tuple).Slice()
+	names := make([]string, len(vars))
+	for i, pv := range vars {
+		names[i] = pv.Name()
+	}
+	renamed := s.declare(names, synthesize)
+	for i, pv := range vars {
+		if typ := sub.apply(pv.Type()); renamed[i] != pv.Name() || typ != pv.Type() {
+			vars[i] = types.NewVar(pv.Pos(), pv.Pkg(), renamed[i], typ)
+		}
+	}
+	return types.NewTuple(vars...)
+}
+
+// declareTypeParams declares the given type parameters in the scope as
+// [scope.declare] and returns their copies, with the new names and the
+// constraints substituted and qualified using the given importer, with the
+// substitution mapping the original type parameters to the copies.
+func (s *scope) declareTypeParams(list *types.TypeParamList, imp *importer.Importer) ([]*types.TypeParam, substitution) {
+	tps := sequence.FromSequenceable(list).Slice()
+	names := make([]string, len(tps))
+	for i, tp := range tps {
+		names[i] = tp.Obj().Name()
+	}
+	renamed := s.declare(names, false)
+	copies := make([]*types.TypeParam, len(tps))
+	sub := make(substitution, len(tps))
+	for i, tp := range tps {
+		obj := types.NewTypeName(tp.Obj().Pos(), tp.Obj().Pkg(), renamed[i], nil)
+		copies[i] = types.NewTypeParam(obj, nil)
+		sub[tp.Obj()] = copies[i]
+	}
+	for i, tp := range tps {
+		copies[i].SetConstraint(NewQualifiedType(sub.apply(tp.Constraint()), imp))
+	}
+	return copies, sub
+}
+
+// substitution maps the type names of type parameters to the types replacing
+// them and the type names of named types and aliases to the [localType]
+// referring to them in the generated code.
+type substitution map[*types.TypeName]types.Type
+
+// apply returns the given type replacing the type parameters, the named
+// types and the aliases in it according to the substitution. The type is returned as is if it
+// does not contain any of them.
+func (sub substitution) apply(typ types.Type) types.Type {
+	if len(sub) == 0 {
+		return typ
+	}
+	switch t := typ.(type) {
+	case *types.TypeParam:
+		if r, ok := sub[t.Obj()]; ok {
+			return r
+		}
+	case *types.Pointer:
+		if elem := sub.apply(t.Elem()); elem != t.Elem() {
+			return types.NewPointer(elem)
+		}
+	case *types.Slice:
+		if elem := sub.apply(t.Elem()); elem != t.Elem() {
+			return types.NewSlice(elem)
+		}
+	case *types.Array:
+		if elem := sub.apply(t.Elem()); elem != t.Elem() {
+			return types.NewArray(elem, t.Len())
+		}
+	case *types.Chan:
+		if elem := sub.apply(t.Elem()); elem != t.Elem() {
+			return types.NewChan(t.Dir(), elem)
+		}
+	case *types.Map:
+		key, elem := sub.apply(t.Key()), sub.apply(t.Elem())
+		if key != t.Key() || elem != t.Elem() {
+			return types.NewMap(key, elem)
+		}
+	case *types.Tuple:
+		if vars, ok := sub.vars(sequence.FromSequenceable(t).Slice()); ok {
+			return types.NewTuple(vars...)
+		}
+	case *types.Signature:
+		params, results := sub.apply(t.Params()), sub.apply(t.Results())
+		if params != t.Params() || results != t.Results() {
+			return types.NewSignatureType(nil, nil, nil, params.(*types.Tuple), results.(*types.Tuple), t.Variadic())
+		}
+	case *types.Struct:
+		fields := sequence.New(t.NumFields, t.Field).Slice()
+		changed := false
+		for i, f := range fields {
+			if typ := sub.apply(f.Type()); typ != f.Type() {
+				fields[i], changed = types.NewField(f.Pos(), f.Pkg(), f.Name(), typ, f.Embedded()), true
+			}
+		}
+		if changed {
+			return types.NewStruct(fields, sequence.New(t.NumFields, t.Tag).Slice())
+		}
+	case *types.Interface:
+		return sub.iface(t)
+	case *types.Union:
+		terms := sequence.New(t.Len, t.Term).Slice()
+		changed := false
+		for i, term := range terms {
+			if typ := sub.apply(term.Type()); typ != term.Type() {
+				terms[i], changed = types.NewTerm(term.Tilde(), typ), true
+			}
+		}
+		if changed {
+			return types.NewUnion(terms)
+		}
+	case *types.Named:
+		return sub.instance(t, t.Origin(), t.Origin().Obj(), t.TypeArgs())
+	case *types.Alias:
+		// Origin and TypeArgs are available since go1.23
+		if a, ok := typ.(interface {
+			Origin() *types.Alias
+			TypeArgs() *types.TypeList
+		}); ok {
+			return sub.instance(t, a.Origin(), a.Origin().Obj(), a.TypeArgs())
+		}
+	case *QualifiedType:
+		if inner := sub.apply(t.typ); inner != t.typ {
+			return NewQualifiedType(inner, t.imp)
+		}
+	}
+	return typ
+}
+
+// instance returns the given instance of a named type or generic alias with
+// the substituted type arguments, re-instantiating its origin if at least one
+// of them changed. If the type is renamed by the substitution, the returned
+// type refers to the [localType] instead.
+func (sub substitution) instance(typ, origin types.Type, obj *types.TypeName, targs *types.TypeList) types.Type {
+	args := sequence.FromSequenceable(targs).Slice()
+	changed := false
+	for i, arg := range args {
+		if typ := sub.apply(arg); typ != arg {
+			args[i], changed = typ, true
+		}
+	}
+	inst := typ
+	if changed {
+		if i, err := types.Instantiate(nil, origin, args, false); err == nil {
+			inst = i
+		}
+	}
+	if lt, ok := sub[obj].(*localType); ok {
+		return lt.instance(inst, args)
+	}
+	return inst
+}
+
+// vars returns the given variables with the substituted types and true, if at
+// least one of them changed. Otherwise, it returns the variables as is and
+// false.
+func (sub substitution) vars(vars []*types.Var) ([]*types.Var, bool) {
+	changed := false
+	for i, pv := range vars {
+		if typ := sub.apply(pv.Type()); typ != pv.Type() {
+			vars[i], changed = types.NewVar(pv.Pos(), pv.Pkg(), pv.Name(), typ), true
+		}
+	}
+	return vars, changed
+}
+
+// iface returns the given interface with the substituted method signatures and
+// embedded types, or the interface as is if none of them changed.
+func (sub substitution) iface(t *types.Interface) types.Type {
+	changed := false
+	methods := sequence.New(t.NumExplicitMethods, t.ExplicitMethod).Slice()
+	sigs := make([]types.Type, len(methods))
+	for i, fn := range methods {
+		sigs[i] = sub.apply(fn.Type())
+		changed = changed || sigs[i] != fn.Type()
+	}
+	embeddeds := sequence.New(t.NumEmbeddeds, t.EmbeddedType).Slice()
+	for i, e := range embeddeds {
+		if typ := sub.apply(e); typ != e {
+			embeddeds[i], changed = typ, true
+		}
+	}
+	if !changed {
+		return t
+	}
+	for i, fn := range methods {
+		// the receiver is set by types.NewInterfaceType
+		sig := sigs[i].(*types.Signature)
+		sig = types.NewSignatureType(nil, nil, nil, sig.Params(), sig.Results(), sig.Variadic())
+		methods[i] = types.NewFunc(fn.Pos(), fn.Pkg(), fn.Name(), sig)
+	}
+	iface := types.NewInterfaceType(methods, embeddeds)
+	if t.IsImplicit() {
+		iface.MarkImplicit()
+	}
+	return iface.Complete()
+}
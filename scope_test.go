@@ -0,0 +1,104 @@
+package aliaser
+
+import (
+	"go/types"
+	"testing"
+
+	"github.com/marcozac/go-aliaser/importer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/tools/go/packages"
+)
+
+func TestScope(t *testing.T) {
+	sc := newScope("pkg", "F")
+	sc.reserve("r", "_", "")
+	assert.Equal(t, []string{"pkg_", "T"}, sc.declare([]string{"pkg", "T"}, false))
+	assert.Equal(t, []string{"p0", "pkg__", "F_", "r", "T_"}, sc.declare([]string{"_", "pkg_", "F", "r", "T"}, true))
+	assert.Equal(t, []string{"", "_"}, sc.declare([]string{"", "_"}, false))
+}
+
+func TestSubstitution(t *testing.T) {
+	LoadedPackageHelper(t, func(t *testing.T, p *packages.Package) {
+		any := types.Universe.Lookup("any").Type()
+		orig := types.NewTypeParam(types.NewTypeName(0, p.Types, "T", nil), any)
+		repl := types.NewTypeParam(types.NewTypeName(0, p.Types, "U", nil), any)
+		sub := substitution{orig.Obj(): repl}
+		n, ok := p.Types.Scope().Lookup("N").Type().(*types.Named)
+		require.True(t, ok)
+		inst, err := types.Instantiate(nil, n, []types.Type{orig}, false)
+		require.NoError(t, err)
+		param := types.NewVar(0, p.Types, "t", orig)
+		sig := types.NewSignatureType(nil, nil, nil, types.NewTuple(param), types.NewTuple(), false)
+		iface := types.NewInterfaceType([]*types.Func{types.NewFunc(0, p.Types, "Foo", sig)}, nil).Complete()
+		union := types.NewInterfaceType(nil, []types.Type{
+			types.NewUnion([]*types.Term{types.NewTerm(true, types.Typ[types.String]), types.NewTerm(false, types.NewSlice(orig))}),
+		})
+		union.MarkImplicit()
+		for _, tt := range []struct {
+			typ  types.Type
+			want string
+		}{
+			{orig, "U"},
+			{types.NewPointer(orig), "*U"},
+			{types.NewSlice(orig), "[]U"},
+			{types.NewArray(orig, 2), "[2]U"},
+			{types.NewChan(types.RecvOnly, orig), "<-chan U"},
+			{types.NewMap(types.Typ[types.String], orig), "map[string]U"},
+			{sig, "func(t U)"},
+			{types.NewStruct([]*types.Var{types.NewField(0, p.Types, "F", orig, false)}, []string{`json:"f"`}), "struct{F U \"json:\\\"f\\\"\"}"},
+			{iface, "interface{Foo(t U)}"},
+			{union, "~string | []U"},
+			{inst, "N[U]"},
+			{NewQualifiedType(orig, importer.New()), "U"},
+			{types.Typ[types.Int], "int"},
+		} {
+			assert.Equal(t, tt.want, types.TypeString(sub.apply(tt.typ), types.RelativeTo(p.Types)))
+		}
+		// unchanged types are returned as is
+		assert.Same(t, sig, substitution{repl.Obj(): orig}.apply(sig))
+		assert.Same(t, iface, substitution{repl.Obj(): orig}.apply(iface))
+	})
+}
+
+func TestSubstitutionAlias(t *testing.T) {
+	LoadedPackageHelper(t, func(t *testing.T, p *packages.Package) {
+		any := types.Universe.Lookup("any").Type()
+		orig := types.NewTypeParam(types.NewTypeName(0, p.Types, "T", nil), any)
+		repl := types.NewTypeParam(types.NewTypeName(0, p.Types, "json_", nil), any)
+		tp := types.NewTypeParam(types.NewTypeName(0, p.Types, "E", nil), any)
+		alias := types.NewAlias(types.NewTypeName(0, p.Types, "L", nil), types.NewSlice(tp))
+		generic, ok := types.Type(alias).(interface{ SetTypeParams([]*types.TypeParam) })
+		if !ok {
+			t.Skip("generic aliases are not supported")
+		}
+		generic.SetTypeParams([]*types.TypeParam{tp})
+		inst, err := types.Instantiate(nil, alias, []types.Type{orig}, false)
+		if err != nil {
+			t.Skipf("generic aliases are not supported: %v", err)
+		}
+		sig := types.NewSignatureType(nil, nil, nil,
+			types.NewTuple(types.NewVar(0, p.Types, "x", inst)),
+			types.NewTuple(types.NewVar(0, p.Types, "", inst)),
+			false,
+		)
+		sub := substitution{orig.Obj(): repl}
+		assert.Equal(t, "func(x L[json_]) L[json_]", types.TypeString(sub.apply(sig), types.RelativeTo(p.Types)))
+		sub[alias.Obj()] = newLocalType(alias, "RenamedL", importer.New())
+		assert.Equal(t, "func(x RenamedL[json_]) RenamedL[json_]", types.TypeString(sub.apply(sig), types.RelativeTo(p.Types)))
+		// unchanged types are returned as is
+		assert.Same(t, inst, substitution{repl.Obj(): orig}.apply(inst))
+	})
+}
+
+func TestScopeDeclareTypeParams(t *testing.T) {
+	LoadedPackageHelper(t, func(t *testing.T, p *packages.Package) {
+		sig := p.Types.Scope().Lookup("Shadow").Type().(*types.Signature)
+		tps, sub := newScope("pkg", "json").declareTypeParams(sig.TypeParams(), importer.New())
+		require.Len(t, tps, 2)
+		assert.Equal(t, "pkg_", tps[0].Obj().Name())
+		assert.Equal(t, "json_", tps[1].Obj().Name())
+		assert.Equal(t, "~string", tps[1].Constraint().String())
+		assert.Same(t, tps[0], sub.apply(sig.TypeParams().At(0)))
+	})
+}
@@ -0,0 +1,227 @@
+package aliaser
+
+import "fmt"
+
+// VarStrategy is the strategy used to generate the alias of a variable.
+type VarStrategy int
+
+const (
+	// VarCopy is the default strategy. It declares a new variable initialized
+	// with the value of the original one when the package is initialized.
+	// Further changes to the original variable are not reflected by the alias.
+	//
+	// Example:
+	//
+	//	var B = pkg.B
+	VarCopy VarStrategy = iota
+
+	// VarPointer declares a new variable initialized with a pointer to the
+	// original one. Any change to the original variable is visible through the
+	// alias and vice versa.
+	//
+	// Example:
+	//
+	//	var B = &pkg.B
+	VarPointer
+
+	// VarAccessors declares a getter function, with the same name of the
+	// original variable, and a setter function, with the same name prefixed
+	// by "Set", that read and write the original variable.
+	//
+	// Example:
+	//
+	//	func B() string { return pkg.B }
+	//	func SetB(v string) { pkg.B = v }
+	VarAccessors
+)
+
+var varStrategyNames = [...]string{
+	VarCopy:      "copy",
+	VarPointer:   "pointer",
+	VarAccessors: "accessors",
+}
+
+// String returns the name of the strategy.
+func (s VarStrategy) String() string {
+	if s < 0 || int(s) >= len(varStrategyNames) {
+		return fmt.Sprintf("VarStrategy(%d)", s)
+	}
+	return varStrategyNames[s]
+}
+
+// ParseVarStrategy returns the [VarStrategy] with the given name, as returned
+// by [VarStrategy.String], or an error if the name is unknown.
+func ParseVarStrategy(name string) (VarStrategy, error) {
+	for s, n := range varStrategyNames {
+		if n == name {
+			return VarStrategy(s), nil
+		}
+	}
+	return 0, fmt.Errorf("unknown variable strategy: %q", name)
+}
+
+// DeprecatedPolicy is the policy applied to the objects of the loaded package
+// marked as deprecated, that is, whose doc comment has a paragraph starting
+// with "Deprecated: ".
+type DeprecatedPolicy int
+
+const (
+	// DeprecatedPropagate is the default policy. It generates the aliases of
+	// the deprecated objects, propagating the deprecation notice onto them, so
+	// that tools like staticcheck and gopls report their usage.
+	DeprecatedPropagate DeprecatedPolicy = iota
+
+	// DeprecatedExclude excludes the deprecated objects from the aliases.
+	DeprecatedExclude
+
+	// DeprecatedError makes the generation fail if any of the objects to alias
+	// is deprecated, returning an error wrapping [ErrDeprecated].
+	DeprecatedError
+)
+
+var deprecatedPolicyNames = [...]string{
+	DeprecatedPropagate: "propagate",
+	DeprecatedExclude:   "exclude",
+	DeprecatedError:     "error",
+}
+
+// String returns the name of the policy.
+func (p DeprecatedPolicy) String() string {
+	if p < 0 || int(p) >= len(deprecatedPolicyNames) {
+		return fmt.Sprintf("DeprecatedPolicy(%d)", p)
+	}
+	return deprecatedPolicyNames[p]
+}
+
+// ParseDeprecatedPolicy returns the [DeprecatedPolicy] with the given name, as
+// returned by [DeprecatedPolicy.String], or an error if the name is unknown.
+func ParseDeprecatedPolicy(name string) (DeprecatedPolicy, error) {
+	for p, n := range deprecatedPolicyNames {
+		if n == name {
+			return DeprecatedPolicy(p), nil
+		}
+	}
+	return 0, fmt.Errorf("unknown deprecated policy: %q", name)
+}
+
+// FuncStrategy is the strategy used to generate the alias of a function.
+type FuncStrategy int
+
+const (
+	// FuncWrap is the default strategy. It declares a new function with the
+	// same signature of the original one that calls it.
+	//
+	// Example:
+	//
+	//	func C(v int) error { return pkg.C(v) }
+	FuncWrap FuncStrategy = iota
+
+	// FuncAssign declares a new variable initialized with the original
+	// function. Since a generic function cannot be used without instantiation,
+	// the generic functions are always wrapped.
+	//
+	// Example:
+	//
+	//	var C = pkg.C
+	FuncAssign
+)
+
+var funcStrategyNames = [...]string{
+	FuncWrap:   "wrap",
+	FuncAssign: "assign",
+}
+
+// String returns the name of the strategy.
+func (s FuncStrategy) String() string {
+	if s < 0 || int(s) >= len(funcStrategyNames) {
+		return fmt.Sprintf("FuncStrategy(%d)", s)
+	}
+	return funcStrategyNames[s]
+}
+
+// ParseFuncStrategy returns the [FuncStrategy] with the given name, as returned
+// by [FuncStrategy.String], or an error if the name is unknown.
+func ParseFuncStrategy(name string) (FuncStrategy, error) {
+	for s, n := range funcStrategyNames {
+		if n == name {
+			return FuncStrategy(s), nil
+		}
+	}
+	return 0, fmt.Errorf("unknown function strategy: %q", name)
+}
+
+// DuplicatePolicy is the policy applied when the name of an alias is already
+// declared, by another alias or in the target package (see [WithTargetDir]).
+type DuplicatePolicy int
+
+const (
+	// OnDuplicateSkip is the default behavior when a duplicate object name is
+	// found. It skips the object and does not generate an alias for it.
+	OnDuplicateSkip DuplicatePolicy = iota
+
+	// OnDuplicateReplace replaces the old object (even if it is a different
+	// kind of object) with the new one.
+	OnDuplicateReplace
+
+	// OnDuplicatePanic panics when a duplicate object name is found.
+	OnDuplicatePanic
+
+	// OnDuplicatePrefix keeps both objects, prefixing the name of the alias of
+	// the new one with the capitalized name of its package. If the prefixed
+	// name is also in use, it is suffixed with underscores until it is not.
+	// It is useful to merge several packages (see [MergePackages]).
+	//
+	// Example:
+	//
+	//	Error = api.Error
+	//	ErrorsError = errors.Error
+	OnDuplicatePrefix
+
+	// OnDuplicateRename keeps both objects, renaming the alias of the new one
+	// with the function set by [RenameDuplicates]. Without it, the alias is
+	// prefixed with the capitalized name of its package, as with
+	// [OnDuplicatePrefix]. If the new name is also in use, it is suffixed with
+	// underscores until it is not.
+	//
+	// Example:
+	//
+	//	// RenameDuplicates(func(obj types.Object, name string) string {
+	//	// 	return name + "Of" + strings.ToUpper(obj.Pkg().Name())
+	//	// })
+	//	Error = api.Error
+	//	ErrorOfERRORS = errors.Error
+	OnDuplicateRename
+
+	// OnDuplicateError skips the objects whose alias name is already in use,
+	// collecting them into a [*DuplicateError] returned by [New] or by the
+	// method adding them (e.g. [Aliaser.AddTypes]).
+	OnDuplicateError
+)
+
+var duplicatePolicyNames = [...]string{
+	OnDuplicateSkip:    "skip",
+	OnDuplicateReplace: "replace",
+	OnDuplicatePanic:   "panic",
+	OnDuplicatePrefix:  "prefix",
+	OnDuplicateRename:  "rename",
+	OnDuplicateError:   "error",
+}
+
+// String returns the name of the policy.
+func (p DuplicatePolicy) String() string {
+	if p < 0 || int(p) >= len(duplicatePolicyNames) {
+		return fmt.Sprintf("DuplicatePolicy(%d)", p)
+	}
+	return duplicatePolicyNames[p]
+}
+
+// ParseDuplicatePolicy returns the [DuplicatePolicy] with the given name, as
+// returned by [DuplicatePolicy.String], or an error if the name is unknown.
+func ParseDuplicatePolicy(name string) (DuplicatePolicy, error) {
+	for p, n := range duplicatePolicyNames {
+		if n == name {
+			return DuplicatePolicy(p), nil
+		}
+	}
+	return 0, fmt.Errorf("unknown duplicate policy: %q", name)
+}
@@ -0,0 +1,54 @@
+package aliaser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVarStrategy(t *testing.T) {
+	for _, s := range []VarStrategy{VarCopy, VarPointer, VarAccessors} {
+		ps, err := ParseVarStrategy(s.String())
+		require.NoError(t, err)
+		assert.Equal(t, s, ps)
+	}
+	assert.Equal(t, "VarStrategy(10)", VarStrategy(10).String())
+	_, err := ParseVarStrategy("unknown")
+	assert.Error(t, err)
+}
+
+func TestDeprecatedPolicy(t *testing.T) {
+	for _, p := range []DeprecatedPolicy{DeprecatedPropagate, DeprecatedExclude, DeprecatedError} {
+		pp, err := ParseDeprecatedPolicy(p.String())
+		require.NoError(t, err)
+		assert.Equal(t, p, pp)
+	}
+	assert.Equal(t, "DeprecatedPolicy(10)", DeprecatedPolicy(10).String())
+	_, err := ParseDeprecatedPolicy("unknown")
+	assert.Error(t, err)
+}
+
+func TestFuncStrategy(t *testing.T) {
+	for _, s := range []FuncStrategy{FuncWrap, FuncAssign} {
+		ps, err := ParseFuncStrategy(s.String())
+		require.NoError(t, err)
+		assert.Equal(t, s, ps)
+	}
+	assert.Equal(t, "FuncStrategy(10)", FuncStrategy(10).String())
+	_, err := ParseFuncStrategy("unknown")
+	assert.Error(t, err)
+}
+
+func TestDuplicatePolicy(t *testing.T) {
+	for _, p := range []DuplicatePolicy{
+		OnDuplicateSkip, OnDuplicateReplace, OnDuplicatePanic, OnDuplicatePrefix, OnDuplicateRename, OnDuplicateError,
+	} {
+		pp, err := ParseDuplicatePolicy(p.String())
+		require.NoError(t, err)
+		assert.Equal(t, p, pp)
+	}
+	assert.Equal(t, "DuplicatePolicy(10)", DuplicatePolicy(10).String())
+	_, err := ParseDuplicatePolicy("unknown")
+	assert.Error(t, err)
+}
@@ -0,0 +1,138 @@
+package aliaser
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+)
+
+// WithTargetDir sets the directory of the target package, whose package-scope
+// declarations are collected before loading the packages to alias, so that
+// the aliases do not collide with them. A name already declared in the target
+// package is handled according to the [OnDuplicate] behavior, as if declared
+// by another alias, except for [OnDuplicateReplace] that skips the alias,
+// since the existing declaration cannot be replaced.
+//
+// Only the Go files of the target package are considered, including its
+// internal test files, while the generated files, i.e. the ones starting with
+// the header of the generated code (see [WithHeader]) or with the standard
+// "// Code generated ... DO NOT EDIT." comment, are skipped, since they are
+// assumed to be generated by a previous run. If the directory does not exist, the target
+// package is assumed to be empty.
+//
+// Example:
+//
+//	// foo/client.go
+//	package foo
+//
+//	type Client struct{ /* ... */ }
+//
+//	// generated with WithTargetDir("foo") and OnDuplicate(OnDuplicatePrefix)
+//	// foo/alias.go
+//	type PkgClient = pkg.Client
+func WithTargetDir(dir string) Option {
+	return option(func(c *Config) {
+		c.targetDir = dir
+	})
+}
+
+// TargetNames returns the names declared in the target package, as collected
+// from the target directory (see [WithTargetDir]).
+func (a *Aliaser) TargetNames() []string {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.targetNames
+}
+
+// loadTargetNames adds the names declared in the target directory, if any,
+// to the names declared in the generated code.
+func (a *Aliaser) loadTargetNames() error {
+	if a.targetDir == "" {
+		return nil
+	}
+	names, err := targetNames(a.targetDir, a.TargetPackage, a.Header)
+	if err != nil {
+		return fmt.Errorf("target names: %w", err)
+	}
+	for _, name := range names {
+		a.names.PutNX(name, targetId)
+	}
+	a.targetNames = names
+	return nil
+}
+
+// targetNames returns the sorted names declared at package scope by the Go
+// files of the given package in the given directory, skipping the generated
+// ones, that is, the files starting with the given header or with the
+// standard "// Code generated ... DO NOT EDIT." comment (see [ast.IsGenerated]).
+func targetNames(dir, pkg, header string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		return nil, nil
+	case err != nil:
+		return nil, fmt.Errorf("read directory: %w", err)
+	}
+	fset := token.NewFileSet()
+	var names []string
+	for _, e := range entries {
+		if e.IsDir() || filepath.Ext(e.Name()) != ".go" {
+			continue
+		}
+		name := filepath.Join(dir, e.Name())
+		data, err := os.ReadFile(name)
+		if err != nil {
+			return nil, fmt.Errorf("read file: %w", err)
+		}
+		if header != "" && bytes.HasPrefix(data, []byte(header)) {
+			continue
+		}
+		f, err := parser.ParseFile(fset, name, data, parser.ParseComments|parser.SkipObjectResolution)
+		if err != nil {
+			return nil, fmt.Errorf("parse: %w", err)
+		}
+		if f.Name.Name == pkg && !ast.IsGenerated(f) {
+			names = append(names, declNames(f)...)
+		}
+	}
+	slices.Sort(names)
+	return slices.Compact(names), nil
+}
+
+// declNames returns the names declared at package scope by the given file,
+// excluding the methods, the init functions and the blank identifiers.
+func declNames(f *ast.File) []string {
+	var names []string
+	add := func(ids ...*ast.Ident) {
+		for _, id := range ids {
+			if id.Name != "_" && id.Name != "init" {
+				names = append(names, id.Name)
+			}
+		}
+	}
+	for _, decl := range f.Decls {
+		switch decl := decl.(type) {
+		case *ast.FuncDecl:
+			if decl.Recv == nil {
+				add(decl.Name)
+			}
+		case *ast.GenDecl:
+			for _, spec := range decl.Specs {
+				switch spec := spec.(type) {
+				case *ast.ValueSpec:
+					add(spec.Names...)
+				case *ast.TypeSpec:
+					add(spec.Name)
+				}
+			}
+		}
+	}
+	return names
+}
@@ -0,0 +1,122 @@
+package aliaser
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeTargetFiles writes the given files to a temporary directory, returning
+// its path.
+func writeTargetFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644))
+	}
+	return dir
+}
+
+func TestTargetNames(t *testing.T) {
+	const header = "// Code generated by aliaser. DO NOT EDIT."
+	dir := writeTargetFiles(t, map[string]string{
+		"client.go": `package out
+
+import "fmt"
+
+const Version, _ = "v2", 0
+
+type (
+	Client struct{}
+	local  int
+)
+
+var DefaultClient, defaultName = &Client{}, "default"
+
+func init() {}
+
+func NewClient() *Client { return &Client{} }
+
+func (c *Client) String() string { return fmt.Sprint(c) }
+`,
+		"client_test.go":   "package out\n\nfunc helper() {}\n",
+		"external_test.go": "package out_test\n\nfunc External() {}\n",
+		"alias.go":         header + "\n\npackage out\n\nconst Generated = 1\n",
+		"old_alias.go":     "// Code generated by aliaser v0.1.0. DO NOT EDIT.\n\npackage out\n\nconst Old = 1\n",
+		"README.md":        "# out\n",
+	})
+	names, err := targetNames(dir, TestTarget, header)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"Client", "DefaultClient", "NewClient", "Version", "defaultName", "helper", "local",
+	}, names)
+
+	t.Run("NotExist", func(t *testing.T) {
+		names, err := targetNames(filepath.Join(dir, "missing"), TestTarget, header)
+		assert.NoError(t, err)
+		assert.Empty(t, names)
+	})
+	t.Run("ParseError", func(t *testing.T) {
+		dir := writeTargetFiles(t, map[string]string{"bad.go": "package out\n\nfunc {"})
+		_, err := targetNames(dir, TestTarget, header)
+		assert.Error(t, err)
+	})
+}
+
+func TestWithTargetDir(t *testing.T) {
+	dir := writeTargetFiles(t, map[string]string{
+		"client.go": "package out\n\ntype Client struct{}\n\nfunc NewClient() *Client { return &Client{} }\n",
+	})
+	generate := func(t *testing.T, opts ...Option) (*Aliaser, string) {
+		t.Helper()
+		a, err := New(&Config{TargetPackage: TestTarget, Pattern: TestRenamePattern}, append([]Option{WithTargetDir(dir)}, opts...)...)
+		require.NoError(t, err)
+		buf := new(bytes.Buffer)
+		require.NoError(t, a.Generate(buf))
+		return a, buf.String()
+	}
+	t.Run("Names", func(t *testing.T) {
+		a, _ := generate(t)
+		assert.Equal(t, []string{"Client", "NewClient"}, a.TargetNames())
+	})
+	t.Run("Skip", func(t *testing.T) {
+		_, out := generate(t, OnDuplicate(OnDuplicateSkip))
+		assert.NotContains(t, out, "Client = rename.Client")
+		assert.NotContains(t, out, "func NewClient()")
+		assert.Contains(t, out, "Version = rename.Version")
+	})
+	t.Run("Replace", func(t *testing.T) {
+		// the declarations of the target package are never replaced
+		_, out := generate(t, OnDuplicate(OnDuplicateReplace))
+		assert.NotContains(t, out, "Client = rename.Client")
+		assert.NotContains(t, out, "func NewClient()")
+	})
+	t.Run("Prefix", func(t *testing.T) {
+		_, out := generate(t, OnDuplicate(OnDuplicatePrefix))
+		assert.Contains(t, out, "RenameClient = rename.Client")
+		assert.Contains(t, out, "func RenameNewClient() *RenameClient {")
+	})
+	t.Run("Panic", func(t *testing.T) {
+		assert.PanicsWithError(t, "duplicate object name: Client: declared in the target package", func() {
+			_, _ = New(&Config{TargetPackage: TestTarget, Pattern: TestRenamePattern}, WithTargetDir(dir), OnDuplicate(OnDuplicatePanic))
+		})
+	})
+	t.Run("Platforms", func(t *testing.T) {
+		a, err := New(&Config{TargetPackage: TestTarget, Pattern: TestRenamePattern},
+			WithTargetDir(dir), OnDuplicate(OnDuplicateSkip), WithPlatforms(Platform{GOOS: "linux"}, Platform{GOOS: "darwin"}))
+		require.NoError(t, err)
+		buf := new(bytes.Buffer)
+		require.NoError(t, a.Generate(buf))
+		assert.NotContains(t, buf.String(), "Client = rename.Client")
+		assert.Contains(t, buf.String(), "Version = rename.Version")
+	})
+	t.Run("Error", func(t *testing.T) {
+		dir := writeTargetFiles(t, map[string]string{"bad.go": "package out\n\nfunc {"})
+		_, err := New(&Config{TargetPackage: TestTarget, Pattern: TestRenamePattern}, WithTargetDir(dir))
+		assert.ErrorContains(t, err, "target names")
+	})
+}
@@ -0,0 +1,111 @@
+package aliaser
+
+import "go/types"
+
+// Transformation is the set of changes applied to an object of the loaded
+// package before its alias is added. It is initialized according to the
+// configuration and passed to each [Transformer] in order, so that a
+// transformer sees the changes made by the previous ones.
+type Transformation struct {
+	// Name is the name of the alias. Renaming an object may cause a conflict
+	// with another one, resolved according to the [OnDuplicate] behavior.
+	//
+	// Default: the name of the object, renamed according to the renaming
+	// rules, if any (e.g. [RenamePrefix]).
+	Name string
+
+	// Drop excludes the object from the aliases.
+	Drop bool
+
+	// VarStrategy is the strategy used to generate the alias of a variable.
+	// It is ignored for the other objects.
+	//
+	// Default: the strategy set by [WithVarStrategy] for the variable.
+	VarStrategy VarStrategy
+
+	// FuncStrategy is the strategy used to generate the alias of a function.
+	// It is ignored for the other objects and for the generic functions, that
+	// are always wrapped.
+	//
+	// Default: [FuncAssign] if [Config.AssignFunctions] is true, otherwise
+	// [FuncWrap].
+	FuncStrategy FuncStrategy
+}
+
+// Transformer is the interface implemented by the types that change the
+// objects of the loaded package before their aliases are added, e.g. to
+// rename them, to change their strategy or to drop them.
+//
+// The transformers are called, in the order they are set by
+// [WithTransformers], only for the objects that are not excluded by the
+// other options (e.g. [ExcludeNames] or [WithFilter]).
+type Transformer interface {
+	// Transform applies the changes to the given transformation of the
+	// given object.
+	Transform(obj types.Object, t *Transformation)
+}
+
+// TransformerFunc is an adapter to use an ordinary function as [Transformer].
+//
+// Example:
+//
+//	// rename the constructors from NewXxx to MakeXxx
+//	aliaser.TransformerFunc(func(obj types.Object, t *aliaser.Transformation) {
+//		if name, ok := strings.CutPrefix(t.Name, "New"); ok {
+//			t.Name = "Make" + name
+//		}
+//	})
+type TransformerFunc func(obj types.Object, t *Transformation)
+
+// Transform calls fn(obj, t).
+func (fn TransformerFunc) Transform(obj types.Object, t *Transformation) {
+	fn(obj, t)
+}
+
+// WithTransformers adds the given transformers to the ones applied to the
+// objects of the loaded package. See [Transformer] for more details.
+//
+// Example:
+//
+//	// assign the functions returning an error and use the accessors for the
+//	// variables of an interface type
+//	aliaser.WithTransformers(aliaser.TransformerFunc(func(obj types.Object, t *aliaser.Transformation) {
+//		switch obj := obj.(type) {
+//		case *types.Func:
+//			if res := obj.Type().(*types.Signature).Results(); res.Len() == 1 && res.At(0).Type().String() == "error" {
+//				t.FuncStrategy = aliaser.FuncAssign
+//			}
+//		case *types.Var:
+//			if types.IsInterface(obj.Type()) {
+//				t.VarStrategy = aliaser.VarAccessors
+//			}
+//		}
+//	}))
+func WithTransformers(ts ...Transformer) Option {
+	return option(func(c *Config) {
+		c.transformers = append(c.transformers, ts...)
+	})
+}
+
+// transformation returns the transformation of the given object as set by
+// the configuration, without applying the transformers.
+func (c *Config) transformation(o types.Object) *Transformation {
+	t := &Transformation{
+		Name:        c.renamer.rename(o.Name()),
+		VarStrategy: c.varStrategyOf(o.Name()),
+	}
+	if c.AssignFunctions {
+		t.FuncStrategy = FuncAssign
+	}
+	return t
+}
+
+// transform returns the transformation of the given object applying the
+// transformers in order.
+func (c *Config) transform(o types.Object) *Transformation {
+	t := c.transformation(o)
+	for _, tr := range c.transformers {
+		tr.Transform(o, t)
+	}
+	return t
+}
@@ -0,0 +1,110 @@
+package aliaser
+
+import (
+	"bytes"
+	"go/types"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransformers(t *testing.T) {
+	generate := func(t *testing.T, a *Aliaser) string {
+		t.Helper()
+		buf := new(bytes.Buffer)
+		require.NoError(t, a.Generate(buf))
+		return buf.String()
+	}
+	t.Run("Rename", AliaserTest(func(t *testing.T, a *Aliaser) {
+		out := generate(t, a)
+		assert.Contains(t, out, "func Call() {\n\tpkg.C()\n}")
+		assert.Contains(t, out, "func DoJ(")
+		assert.NotContains(t, out, "func C() {")
+	}, WithTransformers(
+		TransformerFunc(func(obj types.Object, t *Transformation) {
+			if obj.Name() == "C" {
+				t.Name = "Call"
+			}
+		}),
+		TransformerFunc(func(obj types.Object, t *Transformation) {
+			if _, ok := obj.(*types.Func); ok && len(t.Name) == 1 {
+				t.Name = "Do" + t.Name // not applied to Call
+			}
+		}),
+	)))
+	t.Run("RenameConflict", AliaserTest(func(t *testing.T, a *Aliaser) {
+		out := generate(t, a)
+		assert.Contains(t, out, "A = pkg.A")
+		assert.Contains(t, out, "func PkgA() {\n\tpkg.C()\n}")
+	}, OnDuplicate(OnDuplicatePrefix), WithTransformers(TransformerFunc(func(obj types.Object, t *Transformation) {
+		if obj.Name() == "C" {
+			t.Name = "A"
+		}
+	}))))
+	t.Run("Drop", AliaserTest(func(t *testing.T, a *Aliaser) {
+		assert.Empty(t, a.Constants())
+		assert.NotEmpty(t, a.Variables())
+	}, WithTransformers(TransformerFunc(func(obj types.Object, t *Transformation) {
+		_, t.Drop = obj.(*types.Const)
+	}))))
+	t.Run("Strategy", AliaserTest(func(t *testing.T, a *Aliaser) {
+		out := generate(t, a)
+		assert.Contains(t, out, "B = &pkg.B")
+		assert.Contains(t, out, "C = pkg.C")
+		assert.Contains(t, out, "func W() pkg.P[int, string] {")
+		for _, fn := range a.Functions() {
+			assert.Equal(t, fn.Name() == "C", fn.Assigned(), fn.Name())
+		}
+	}, WithTransformers(TransformerFunc(func(obj types.Object, t *Transformation) {
+		switch obj.Name() {
+		case "B":
+			t.VarStrategy = VarPointer
+		case "C", "S": // S is generic, always wrapped
+			t.FuncStrategy = FuncAssign
+		}
+	}))))
+	t.Run("StrategyDefault", AliaserTest(func(t *testing.T, a *Aliaser) {
+		out := generate(t, a)
+		assert.Contains(t, out, "func C() {")
+		assert.Contains(t, out, "Blank = pkg.Blank")
+	}, AssignFunctions(true), WithTransformers(TransformerFunc(func(obj types.Object, tr *Transformation) {
+		if obj.Name() == "C" {
+			assert.Equal(t, FuncAssign, tr.FuncStrategy)
+			tr.FuncStrategy = FuncWrap
+		}
+	}))))
+	t.Run("InvalidName", func(t *testing.T) {
+		_, err := New(&Config{TargetPackage: TestTarget, Pattern: TestPattern}, WithTransformers(
+			TransformerFunc(func(obj types.Object, t *Transformation) { t.Name += "-" }),
+		))
+		assert.ErrorIs(t, err, ErrInvalidAliasName)
+	})
+}
+
+func TestWithFilter(t *testing.T) {
+	t.Run("Types", AliaserTest(func(t *testing.T, a *Aliaser) {
+		assert.Empty(t, a.Constants())
+		assert.Empty(t, a.Variables())
+		assert.Empty(t, a.Functions())
+		require.NotEmpty(t, a.Types())
+		for _, tn := range a.Types() {
+			assert.True(t, types.IsInterface(tn.Type()), tn.Name())
+		}
+	}, WithFilter(func(obj types.Object) bool {
+		_, ok := obj.(*types.TypeName)
+		return ok
+	}), WithFilter(func(obj types.Object) bool {
+		return types.IsInterface(obj.Type())
+	})))
+	t.Run("BeforeTransformers", AliaserTest(func(t *testing.T, a *Aliaser) {
+		for _, c := range a.Constants() {
+			assert.False(t, strings.HasPrefix(c.Name(), "A"))
+		}
+	}, WithFilter(func(obj types.Object) bool {
+		return obj.Name() != "A"
+	}), WithTransformers(TransformerFunc(func(obj types.Object, _ *Transformation) {
+		assert.NotEqual(t, "A", obj.Name())
+	}))))
+}
@@ -2,22 +2,41 @@
 
 import (
 	"go/types"
-	"slices"
+	"strings"
 	"sync"
 
 	"github.com/marcozac/go-aliaser/importer"
+	"github.com/marcozac/go-aliaser/util/maps"
 	"github.com/marcozac/go-aliaser/util/sequence"
 )
 
 // Signature is the type used to represent a function signature in the loaded
 // package. It contains the original signature and the importer used to generate
-// the aliases. It also contains a wrapper signature that replaces the parameter
-// and result names on conflict with the package aliases.
+// the aliases. It also contains a wrapper signature that renames the type
+// parameters, parameters and results that would conflict with the package
+// aliases or with each other.
 type Signature struct {
 	*types.Signature
 	imp     *importer.Importer
 	wrapper *types.Signature
 	once    sync.Once
+
+	// outer is the list of the names declared in the generated package, other
+	// than the package aliases, that the wrapper must not shadow, like the
+	// name of the function itself or, for methods, the name of the receiver
+	// base type.
+	outer []string
+
+	// recvTypeParams are the renamed receiver type parameters.
+	recvTypeParams []*types.TypeParam
+
+	// sub maps the original type parameters to the renamed ones and the
+	// local types to the original ones.
+	sub substitution
+
+	// locals are the types declared in the generated code to be used in the
+	// wrapper in place of the original ones (see [localType]).
+	locals substitution
 }
 
 // NewSignature returns a new [Signature] with the given signature. The importer
@@ -26,8 +45,11 @@ func NewSignature(sig *types.Signature, imp *importer.Importer) *Signature {
 	return &Signature{Signature: sig, imp: imp}
 }
 
-// Wrapper returns a [types.Signature] that wraps the original one, replacing
-// the parameter and result names on conflict with the package aliases.
+// Wrapper returns a [types.Signature] that wraps the original one, renaming
+// in a single scope-aware pass the type parameters, parameters and results
+// that conflict with the package aliases, with the names of the wrapper outer
+// scope or with each other. The unnamed and blank parameters are given a new
+// name, so that they can be passed to the original function (see [scope]).
 //
 // Since it calls the [importer.Importer.AliasedImports] method for initializing
 // the wrapper and further calls will return the same wrapper, it should be called
@@ -42,28 +64,44 @@ func NewSignature(sig *types.Signature, imp *importer.Importer) *Signature {
 //	}
 func (s *Signature) Wrapper() *types.Signature {
 	s.once.Do(func() {
-		ai := s.imp.AliasedImports()
-		aliases := make([]string, 0, len(ai))
-		for _, alias := range ai {
-			aliases = append(aliases, alias)
+		sc := newScope(append(maps.Values(s.imp.AliasedImports()), s.outer...)...)
+		var typeParams []*types.TypeParam
+		s.recvTypeParams, s.sub = sc.declareTypeParams(s.RecvTypeParams(), s.imp)
+		typeParams, sub := sc.declareTypeParams(s.TypeParams(), s.imp)
+		for tp, typ := range sub {
+			s.sub[tp] = typ
+		}
+		for tn, typ := range s.locals {
+			s.sub[tn] = typ
 		}
-		typeParams := make([]*types.TypeParam, s.TypeParams().Len())
-		sequence.FromSequenceable(s.TypeParams()).
-			ForEachIndex(func(tp *types.TypeParam, i int) {
-				typeParams[i] = types.NewTypeParam(tp.Obj(), tp.Constraint())
-			})
+		sc.reserve(tupleNames(s.Results())...)
+		params := sc.declareTuple(s.Params(), s.sub, true)
+		results := sc.declareTuple(s.Results(), s.sub, false)
 		s.wrapper = types.NewSignatureType(
 			s.Recv(), // always nil
 			nil,      // wrap funcs, not methods
 			typeParams,
-			NewAliasedTuple(aliases, s.Params()),
-			NewAliasedTuple(aliases, s.Results()),
+			params,
+			results,
 			s.Variadic(),
 		)
 	})
 	return s.wrapper
 }
 
+// typeParams returns the type parameters of the wrapper.
+func (s *Signature) typeParams() []*TypeParam {
+	tps := sequence.FromSequenceable(s.Wrapper().TypeParams()).Slice()
+	return wrapTypeParams(tps)
+}
+
+// subst returns the given type replacing the original type parameters with
+// the ones of the wrapper.
+func (s *Signature) subst(typ types.Type) types.Type {
+	s.Wrapper()
+	return s.sub.apply(typ)
+}
+
 // TypeParam is the type used to represent a type parameter in the loaded package.
 // It must be created using the [NewTypeParam] function.
 type TypeParam struct {
@@ -77,6 +115,19 @@ func NewTypeParam(tp *types.TypeParam, imp *importer.Importer) *TypeParam {
 	return &TypeParam{tp}
 }
 
+// wrapTypeParams returns the given type parameters as a slice of [TypeParam].
+// Their constraints must be already qualified, e.g. by [scope.declareTypeParams].
+func wrapTypeParams(tps []*types.TypeParam) []*TypeParam {
+	if len(tps) == 0 {
+		return nil
+	}
+	wrapped := make([]*TypeParam, len(tps))
+	for i, tp := range tps {
+		wrapped[i] = &TypeParam{tp}
+	}
+	return wrapped
+}
+
 // QualifiedType is the type used to represent a type in the loaded package. It
 // contains the original type and the importer used to resolve the package aliases.
 // It must be created using the [NewQualifiedType] function.
@@ -101,6 +152,64 @@ func (qt *QualifiedType) String() string {
 	return types.TypeString(qt.typ, qt.qualifier)
 }
 
+// rawType is a [types.Type] whose string representation is the given one,
+// while the underlying type is the one of the embedded type. It is used to
+// refer to types declared in the generated code, that do not exist in the
+// loaded packages.
+type rawType struct {
+	types.Type
+	str string
+}
+
+// String returns the string representation of the raw type.
+func (rt *rawType) String() string {
+	return rt.str
+}
+
+// localType is a [types.Type] referring to a type declared in the generated
+// code as an alias of a type of the loaded packages with a different name,
+// e.g. a renamed type. It is used in the wrapper signatures in place of the
+// original type, since they are identical.
+//
+// Example:
+//
+//	type HTTPClient = pkg.Client
+//
+//	func NewHTTPClient() *HTTPClient {
+//		return pkg.NewClient()
+//	}
+type localType struct {
+	typeQualifier
+	types.Type
+	name string
+}
+
+// newLocalType returns a new [localType] with the given name referring to the
+// given named type or alias.
+func newLocalType(typ types.Type, name string, imp *importer.Importer) *localType {
+	return &localType{typeQualifier{imp}, typ, name}
+}
+
+// String returns the name of the local type.
+func (lt *localType) String() string {
+	return lt.name
+}
+
+// instance returns a type whose string representation is the local type
+// instantiated with the given type arguments, if any, qualified using the
+// importer, while the underlying type is the one of the given instance of the
+// original type.
+func (lt *localType) instance(inst types.Type, args []types.Type) types.Type {
+	if len(args) == 0 {
+		return &rawType{inst, lt.name}
+	}
+	strs := make([]string, len(args))
+	for i, arg := range args {
+		strs[i] = types.TypeString(arg, lt.qualifier)
+	}
+	return &rawType{inst, lt.name + "[" + strings.Join(strs, ", ") + "]"}
+}
+
 type typeQualifier struct{ imp *importer.Importer }
 
 func (q typeQualifier) qualifier(p *types.Package) string {
@@ -109,12 +218,28 @@ func (q typeQualifier) qualifier(p *types.Package) string {
 
 // NewAliasedTuple returns a new tuple ensuring none of its variable names
 // is in the given aliases list. If a variable name is in the list, it is
-// suffixed with an underscore.
+// suffixed with underscores until it does not conflict with any alias or
+// other variable name.
+//
+// Unnamed and blank variables are given a new unique name, "p" followed by
+// their index, so that they can be referred to in the wrapper body.
+//
+// Example:
+//
+//	func(int, string)                        // func(p0 int, p1 string)
+//	func(_ context.Context, _ int, p1 string) // func(p0 context.Context, p1_ int, p1 string)
 func NewAliasedTuple(aliases []string, tuple *types.Tuple) *types.Tuple {
-	return types.NewTuple(sequence.FromSequenceable(tuple).SliceFunc(func(pv *types.Var) *types.Var {
-		if slices.Contains(aliases, pv.Name()) {
-			return types.NewVar(pv.Pos(), pv.Pkg(), pv.Name()+"_", pv.Type())
+	return newScope(aliases...).declareTuple(tuple, nil, true)
+}
+
+// tupleNames returns the names of the variables of the given tuple, excluding
+// the unnamed and blank ones.
+func tupleNames(tuple *types.Tuple) []string {
+	var names []string
+	sequence.FromSequenceable(tuple).ForEach(func(pv *types.Var) {
+		if name := pv.Name(); name != "" && name != "_" {
+			names = append(names, name)
 		}
-		return pv
-	})...)
+	})
+	return names
 }
@@ -22,3 +22,34 @@ func TestTypeParam(t *testing.T) {
 		assert.Equal(t, "int", bound.String())
 	})
 }
+
+func TestNewAliasedTuple(t *testing.T) {
+	LoadedPackageHelper(t, func(t *testing.T, p *packages.Package) {
+		newVar := func(name string) *types.Var { return types.NewVar(0, p.Types, name, types.Typ[types.Int]) }
+		tuple := NewAliasedTuple([]string{"pkg"}, types.NewTuple(
+			newVar(""), newVar("_"), newVar("p1"), newVar("pkg"), newVar("pkg_"),
+		))
+		names := make([]string, tuple.Len())
+		for i := range names {
+			names[i] = tuple.At(i).Name()
+		}
+		assert.Equal(t, []string{"p0", "p1_", "p1", "pkg__", "pkg_"}, names)
+	})
+}
+
+func TestSignatureWrapper(t *testing.T) {
+	LoadedPackageHelper(t, func(t *testing.T, p *packages.Package) {
+		newVar := func(name string) *types.Var { return types.NewVar(0, p.Types, name, types.Typ[types.Int]) }
+		tp := types.NewTypeParam(types.NewTypeName(0, p.Types, "p0", nil), types.Universe.Lookup("any").Type())
+		sig := types.NewSignatureType(nil, nil, []*types.TypeParam{tp},
+			types.NewTuple(newVar("_"), newVar("_")),
+			types.NewTuple(newVar("p1"), newVar("_")),
+			false,
+		)
+		w := NewSignature(sig, importer.New()).Wrapper()
+		assert.Equal(t, "p0_", w.Params().At(0).Name())
+		assert.Equal(t, "p1_", w.Params().At(1).Name())
+		assert.Equal(t, "p1", w.Results().At(0).Name())
+		assert.Equal(t, "_", w.Results().At(1).Name())
+	})
+}
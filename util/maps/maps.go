@@ -29,3 +29,18 @@ func Values[K comparable, V any](m map[K]V) []V {
 	}
 	return values
 }
+
+// Clone creates and returns a new map containing all the key-value pairs of
+// the given map.
+//
+// NOTE:
+// The keys and the values are shallow copies of those in the map. If they
+// contain any reference, the returned map will hold references to the same
+// objects as the given one.
+func Clone[K comparable, V any](m map[K]V) map[K]V {
+	clone := make(map[K]V, len(m))
+	for k, v := range m {
+		clone[k] = v
+	}
+	return clone
+}
@@ -0,0 +1,63 @@
+package aliaser
+
+import (
+	"errors"
+	"fmt"
+	"go/version"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/mod/modfile"
+)
+
+// GenericAliasVersion is the minimum Go version that supports parameterized
+// type aliases, e.g. "type A[T any] = pkg.A[T]".
+const GenericAliasVersion = "go1.24"
+
+// moduleGoVersion returns the Go version declared by the go directive of the
+// module that contains the given directory. It walks up the directory tree
+// looking for a go.mod file and returns an empty string if none is found or
+// if it has no go directive.
+func moduleGoVersion(dir string) (string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", fmt.Errorf("abs: %w", err)
+	}
+	for {
+		name := filepath.Join(dir, "go.mod")
+		data, err := os.ReadFile(name)
+		switch {
+		case err == nil:
+			f, err := modfile.ParseLax(name, data, nil)
+			if err != nil {
+				return "", fmt.Errorf("parse %s: %w", name, err)
+			}
+			if f.Go == nil {
+				return "", nil
+			}
+			return f.Go.Version, nil
+		case !errors.Is(err, fs.ErrNotExist):
+			return "", fmt.Errorf("read %s: %w", name, err)
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", nil
+		}
+		dir = parent
+	}
+}
+
+// goVersionAtLeast reports whether the given Go version, with or without the
+// "go" prefix, is greater than or equal to min. An empty or invalid version is
+// always considered less than min.
+func goVersionAtLeast(v, min string) bool {
+	if v == "" {
+		return false
+	}
+	if !strings.HasPrefix(v, "go") {
+		v = "go" + v
+	}
+	return version.IsValid(v) && version.Compare(v, min) >= 0
+}
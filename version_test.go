@@ -0,0 +1,40 @@
+package aliaser
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestModuleGoVersion(t *testing.T) {
+	t.Run("Module", func(t *testing.T) {
+		v, err := moduleGoVersion("internal/testing/pkg")
+		require.NoError(t, err)
+		assert.Equal(t, "1.22.0", v)
+	})
+	t.Run("NoGoDirective", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module foo\n"), 0o644))
+		v, err := moduleGoVersion(dir)
+		require.NoError(t, err)
+		assert.Empty(t, v)
+	})
+	t.Run("InvalidModFile", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module\n"), 0o644))
+		_, err := moduleGoVersion(dir)
+		assert.Error(t, err)
+	})
+}
+
+func TestGoVersionAtLeast(t *testing.T) {
+	assert.True(t, goVersionAtLeast("1.24", GenericAliasVersion))
+	assert.True(t, goVersionAtLeast("go1.24.0", GenericAliasVersion))
+	assert.True(t, goVersionAtLeast("1.25rc1", GenericAliasVersion))
+	assert.False(t, goVersionAtLeast("1.23.4", GenericAliasVersion))
+	assert.False(t, goVersionAtLeast("", GenericAliasVersion))
+	assert.False(t, goVersionAtLeast("invalid", GenericAliasVersion))
+}